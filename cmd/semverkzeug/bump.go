@@ -17,6 +17,8 @@
 package main
 
 import (
+	"context"
+
 	"github.com/go-git/go-git/v5/plumbing"
 
 	"github.com/0x5a17ed/semverkzeug/internal/bumper"
@@ -28,24 +30,29 @@ var bumpParts = map[string]bumper.Part{
 	"major": bumper.Major,
 	"minor": bumper.Minor,
 	"patch": bumper.Patch,
+
+	"calendar": bumper.Calendar,
 }
 
 type bumpCmd struct {
-	Part     string         `arg:"" enum:"major,minor,patch" help:"part of the version to bump (major, minor, patch)"`
+	Part     string         `arg:"" enum:"major,minor,patch,calendar" help:"part of the version to bump (major, minor, patch; calendar with --calver)"`
 	ScopeArg *gitrepo.Scope `arg:"true" name:"scope" optional:"" help:"tag scope to bump (defaults to scope derived from --repo)"`
 }
 
 func (c *bumpCmd) Scope() *gitrepo.Scope { return c.ScopeArg }
 
-func (c *bumpCmd) Run(root *cli, repo *gitrepo.Context, head *plumbing.Reference) error {
+func (c *bumpCmd) Run(ctx context.Context, root *cli, repo *gitrepo.Context, head *plumbing.Reference) error {
 	scope, err := effectiveScope(root, repo, c)
 	if err != nil {
 		return err
 	}
+	if err := checkShallowHistory(ctx, root, repo, head, scope); err != nil {
+		return err
+	}
 
 	part := bumpParts[c.Part]
 
-	if _, err := bumper.CreateTag(repo, head, part, scope); err != nil {
+	if _, err := bumper.CreateTag(ctx, repo, head, part, scope); err != nil {
 		return err
 	}
 	return nil
@@ -18,8 +18,12 @@ package main
 
 import (
 	"fmt"
+	"log/slog"
+	"time"
 
 	"github.com/alecthomas/kong"
+
+	"github.com/0x5a17ed/semverkzeug/internal/uiprint"
 )
 
 // versionFlag, if given, prints the embedded program version and exits.
@@ -39,9 +43,58 @@ func (versionFlag) BeforeReset(app *kong.Kong) error {
 // cli is the top-level kong CLI grammar.
 type cli struct {
 	Repo string `short:"C" name:"repo" placeholder:"PATH" help:"git repository path (default is $PWD)"`
+	Ref  string `name:"ref" placeholder:"REV" help:"revision to operate on (default is HEAD)"`
+
+	FetchTags     bool `name:"fetch-tags" help:"fetch tags from origin when no version tag is reachable in a shallow clone"`
+	Deepen        int  `name:"deepen" placeholder:"N" help:"deepen a shallow clone by N commits at a time until a version tag is reachable"`
+	FailOnShallow bool `name:"fail-on-shallow" help:"fail when no version tag is reachable in a shallow clone"`
+
+	Timeout time.Duration `name:"timeout" placeholder:"DURATION" help:"give up after DURATION, like 30s (default is no limit)"`
+
+	CoerceTags bool `name:"coerce-tags" help:"also derive versions from tags that are almost semantic versions, like v1.2 or 1.2.3.4"`
+
+	MaintenanceBranches []string `name:"maintenance-branch" placeholder:"PATTERN" help:"branch name pattern whose * names the only version line the branch releases, like release/* for release/1.4 (patch versions of 1.4) or release/1.x (minor versions of 1); repeatable (default is none)"`
+
+	BranchLabels         bool     `name:"branch-labels" help:"name the checked-out branch in dev versions, like v1.2.4-feat-login.dev.<time>"`
+	DefaultBranches      []string `name:"default-branch" default:"main,master" placeholder:"NAME" help:"branch whose dev versions name no branch with --branch-labels; repeatable (default is main and master)"`
+	BranchLabelMaxLength int      `name:"branch-label-max-length" default:"20" placeholder:"N" help:"shorten branch names in dev versions to N characters, 0 for no limit"`
+	DetachedBranchLabel  string   `name:"detached-branch-label" placeholder:"LABEL" help:"name dev versions of a detached HEAD LABEL with --branch-labels (default is no name)"`
+
+	CalVer string `name:"calver" placeholder:"FORMAT" help:"use calendar versions of FORMAT, like YYYY.0M.MICRO or YY.MM, instead of semantic versions"`
+
+	Submodules string `name:"submodules" enum:",ignore,commit-only,full-recursive" default:"" placeholder:"POLICY" help:"how submodules affect whether a version is dirty: ignore, commit-only or full-recursive (default is diff.ignoreSubmodules, or full-recursive)"`
+
+	Verbose   bool   `short:"v" name:"verbose" xor:"verbosity" help:"also print debug messages, like why tags were skipped"`
+	Quiet     bool   `short:"q" name:"quiet" xor:"verbosity" help:"only print errors"`
+	LogFormat string `name:"log-format" enum:"text,json" default:"text" placeholder:"FORMAT" help:"print messages to stderr as text or as JSON lines (default is text)"`
 
 	Version versionFlag `name:"version" help:"Print version information and quit"`
 
 	Describe describeCmd `cmd:"" help:"Print current version string"`
+	History  historyCmd  `cmd:"" help:"Prints the version of every commit in first-parent history"`
 	Bump     bumpCmd     `cmd:"" help:"Bumps the current version and creates a new tag"`
+	Untag    untagCmd    `cmd:"" help:"Deletes the version tag created last on the current commit, undoing a bump; only shows it without --yes"`
+	Retag    retagCmd    `cmd:"" help:"Moves a version tag to a descendant of its commit, keeping its message and tagger"`
+	Stamp    stampCmd    `cmd:"" help:"Writes the current version into project files"`
+	Render   renderCmd   `cmd:"" help:"Renders a template with the current version"`
+	Exec     execCmd     `cmd:"" help:"Runs a command with the current version in its environment"`
+	Lint     lintCmd     `cmd:"" help:"Reports version tags that make the derived versions surprising, with suggested fixes; fails on errors"`
+	Verify   verifyCmd   `cmd:"" help:"Checks that the current version is exactly a release tag; exits 3 when dirty, 4 when untagged, 5 with conflicting tags, 6 with a lightweight tag, 7 when not the highest version"`
+}
+
+// configureLogging sets up uiprint as the global flags say.
+func (c *cli) configureLogging() {
+	level := slog.LevelInfo
+	switch {
+	case c.Verbose:
+		level = slog.LevelDebug
+	case c.Quiet:
+		level = slog.LevelError
+	}
+
+	format := uiprint.FormatText
+	if c.LogFormat == "json" {
+		format = uiprint.FormatJSON
+	}
+	uiprint.Configure(level, format)
 }
@@ -17,12 +17,15 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/go-git/go-git/v5/plumbing"
 
-	"github.com/0x5a17ed/semverkzeug/internal/floatingversion"
 	"github.com/0x5a17ed/semverkzeug/internal/gitrepo"
+	"github.com/0x5a17ed/semverkzeug/internal/uiprint"
+	"github.com/0x5a17ed/semverkzeug/internal/versioninfo"
 )
 
 type describeCmd struct {
@@ -30,44 +33,161 @@ type describeCmd struct {
 
 	AddCommitHash bool `name:"add-commit-hash" help:"add commit hash as metadata"`
 	NoPrefix      bool `name:"no-prefix" help:"print the version without prefix"`
+	Explain       bool `name:"explain" help:"explain on stderr how the version was derived"`
+
+	RecurseSubmodules bool `name:"recurse-submodules" help:"also print the version of every checked out submodule, each line prefixed with its path"`
 }
 
 func (c *describeCmd) Scope() *gitrepo.Scope { return c.ScopeArg }
 
-func (c *describeCmd) Run(root *cli, repo *gitrepo.Context, head *plumbing.Reference) error {
+func (c *describeCmd) Run(ctx context.Context, root *cli, repo *gitrepo.Context, head *plumbing.Reference) error {
 	scope, err := effectiveScope(root, repo, c)
 	if err != nil {
 		return err
 	}
+	if err := checkShallowHistory(ctx, root, repo, head, scope); err != nil {
+		return err
+	}
 
-	guide, err := gitrepo.BuildGuide(repo, head, scope)
-	if err != nil {
-		return fmt.Errorf("build guide: %w", err)
+	if !c.RecurseSubmodules {
+		return c.print(ctx, "", repo, head, scope)
 	}
 
-	spec, err := floatingversion.Describe(repo, guide)
-	if err != nil {
+	if err := c.print(ctx, ".", repo, head, scope); err != nil {
 		return err
 	}
 
-	// Add the commit hash to the version if requested.
-	if c.AddCommitHash && guide.HasCommit() {
-		abbreviatedHash, err := gitrepo.FindUniqueCommitHashAbbreviation(repo, guide.Commit)
+	submodules, err := gitrepo.LoadSubmodules(repo)
+	if err != nil {
+		return err
+	}
+	for _, sub := range submodules {
+		subHead, err := sub.Context.Repository().Head()
 		if err != nil {
-			return fmt.Errorf("abbreviate commit hash: %w", err)
+			return fmt.Errorf("submodule %s: %w", sub.Path, err)
 		}
-
-		v, err := spec.Version.SetMetadata("g" + abbreviatedHash)
-		if err != nil {
-			return fmt.Errorf("set metadata: %w", err)
+		if err := c.print(ctx, sub.Path, sub.Context, subHead, gitrepo.RootScope()); err != nil {
+			return fmt.Errorf("submodule %s: %w", sub.Path, err)
 		}
-		spec = spec.WithVersion(v)
 	}
+	return nil
+}
 
+// print prints the version of head, prefixed with label unless empty.
+func (c *describeCmd) print(ctx context.Context, label string, repo *gitrepo.Context, head *plumbing.Reference, scope gitrepo.Scope) error {
+	info, err := versioninfo.Collect(ctx, repo, head, scope, versioninfo.Options{
+		AddCommitHash: c.AddCommitHash,
+		Explain:       c.Explain,
+	})
+	if err != nil {
+		return err
+	}
+	if info.Explanation != nil {
+		explain(label, info)
+	}
+	if err := gitrepo.CheckVersionLine(repo, head, info.Spec); err != nil {
+		uiprint.Warning("%s", err.Error())
+	}
+
+	version := info.Version
 	if c.NoPrefix {
-		_, err = fmt.Println(spec.Version.String())
+		version = info.SemVer
+	}
+
+	if label != "" {
+		_, err = fmt.Println(label, version)
 	} else {
-		_, err = fmt.Println(spec.String())
+		_, err = fmt.Println(version)
 	}
 	return err
 }
+
+// explain prints how the version in info was derived, for the
+// repository named label unless empty.
+func explain(label string, info *versioninfo.Info) {
+	ex := info.Explanation
+	res := ex.Resolution
+
+	if label != "" {
+		uiprint.Step("Explaining the version of %s", label)
+	}
+
+	uiprint.Step("Candidate tags, highest first")
+	if len(ex.Candidates) == 0 {
+		uiprint.Substep("none")
+	}
+	for _, tc := range ex.Candidates {
+		switch {
+		case tc.Chosen:
+			uiprint.Substep("%s: chosen", tc.TagName)
+		case tc.Skipped != "":
+			uiprint.Substep("%s: skipped, %s", tc.TagName, tc.Skipped)
+		default:
+			uiprint.Substep("%s: not considered, a higher version was chosen", tc.TagName)
+		}
+	}
+
+	uiprint.Step("History")
+	if ex.Guide.Shallow {
+		uiprint.Hint("the repository is a shallow clone")
+	}
+	switch {
+	case !ex.Guide.HasCommit():
+		uiprint.Substep("no commits")
+	case ex.Guide.MergeBase == nil:
+		uiprint.Substep("no tag reachable from %s, which has %d commit(s)", ex.Guide.Commit.Hash, ex.Guide.Depth)
+	default:
+		uiprint.Substep("merge base %s, %d commit(s) before %s", ex.Guide.MergeBase.Hash, ex.Guide.Depth, ex.Guide.Commit.Hash)
+	}
+
+	uiprint.Step("Worktree")
+	switch wt := res.Worktree; {
+	case !res.AtHead:
+		uiprint.Substep("not consulted, the commit is not checked out")
+	case wt == nil:
+		uiprint.Substep("clean")
+	default:
+		for i := range wt.Entries {
+			e := &wt.Entries[i]
+			uiprint.Substep("%c%c %s (%s)", e.StagingStatus(), e.WorktreeStatus(), e.Path(), e.ModTime().Format(time.RFC3339Nano))
+		}
+		if wt.IndexMTime != nil {
+			uiprint.Substep("index (%s)", wt.IndexMTime.Format(time.RFC3339Nano))
+		}
+		switch {
+		case wt.Previous == nil:
+			uiprint.Hint("no previous state, emitting the latest mtime %s", wt.Emitted.Format(time.RFC3339Nano))
+		case wt.Matched:
+			uiprint.Hint("fingerprint matches the previous state, emitting %s again", wt.Emitted.Format(time.RFC3339Nano))
+		case wt.Floored:
+			uiprint.Hint("fingerprint differs from the previous state, but the latest mtime %s is not after the previous %s, emitting %s",
+				wt.Candidate.Format(time.RFC3339Nano), wt.Previous.Format(time.RFC3339Nano), wt.Emitted.Format(time.RFC3339Nano))
+		default:
+			uiprint.Hint("fingerprint differs from the previous state, emitting the latest mtime %s", wt.Emitted.Format(time.RFC3339Nano))
+		}
+	}
+
+	uiprint.Step("Version")
+	uiprint.Substep("base %s", res.Base.String())
+	if res.DevLabel == "" {
+		uiprint.Substep("released, the commit is tagged and the worktree clean")
+	} else {
+		if res.PatchBumped {
+			uiprint.Substep("patch bumped, the base is no prerelease")
+		}
+		if res.Branch != "" {
+			uiprint.Substep("branch identifier %s", res.Branch)
+		}
+		source := "the commit date"
+		if res.Dirty {
+			source = "the worktree"
+		}
+		uiprint.Substep("dev label %s, from %s", res.DevLabel, source)
+		if res.DevReplaced {
+			uiprint.Substep("replaced the dev label of prerelease %q", res.Base.Version.Prerelease())
+		} else if pre := res.Base.Version.Prerelease(); pre != "" {
+			uiprint.Substep("appended to prerelease %q", pre)
+		}
+	}
+	uiprint.Substep("result %s", info.Version)
+}
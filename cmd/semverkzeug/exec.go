@@ -0,0 +1,133 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"slices"
+	"syscall"
+
+	"github.com/go-git/go-git/v5/plumbing"
+
+	"github.com/0x5a17ed/semverkzeug/internal/gitrepo"
+	"github.com/0x5a17ed/semverkzeug/internal/versioninfo"
+)
+
+// exitCodeError carries an exit code, like a child process', through
+// kong's Run so main can exit with it verbatim.
+type exitCodeError struct {
+	code int
+}
+
+func (e exitCodeError) Error() string {
+	return fmt.Sprintf("exit status %d", e.code)
+}
+
+type execCmd struct {
+	Command []string `arg:"" passthrough:"partial" name:"command" help:"command to run, followed by its arguments"`
+
+	ScopeFlag *gitrepo.Scope `name:"scope" help:"tag scope to describe (defaults to scope derived from --repo)"`
+
+	AddCommitHash bool `name:"add-commit-hash" help:"add commit hash as metadata"`
+}
+
+func (c *execCmd) Scope() *gitrepo.Scope { return c.ScopeFlag }
+
+func (c *execCmd) Run(ctx context.Context, root *cli, repo *gitrepo.Context, head *plumbing.Reference) error {
+	scope, err := effectiveScope(root, repo, c)
+	if err != nil {
+		return err
+	}
+	if err := checkShallowHistory(ctx, root, repo, head, scope); err != nil {
+		return err
+	}
+
+	// Describe exactly once; every step of the child sees the same
+	// version even if the worktree changes while it runs.
+	info, err := versioninfo.Collect(ctx, repo, head, scope, versioninfo.Options{
+		AddCommitHash: c.AddCommitHash,
+	})
+	if err != nil {
+		return err
+	}
+
+	// kong hands over the "--" separator along with the command.
+	argv := c.Command
+	if len(argv) > 0 && argv[0] == "--" {
+		argv = argv[1:]
+	}
+	if len(argv) == 0 {
+		return errors.New("no command given")
+	}
+
+	cmd := exec.Command(argv[0], argv[1:]...)
+	cmd.Env = append(slices.Clone(os.Environ()), info.Environ()...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	// The child shares the terminal's process group, so it receives
+	// interrupts from the terminal on its own.  Keep them from killing
+	// us first, without forwarding them, which would interrupt the
+	// child twice.  SIGTERM usually comes from a supervisor stopping
+	// us alone, so it is forwarded.  Interrupts are caught rather than
+	// ignored, as the child would inherit ignoring them.
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("start command: %w", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case sig := <-signals:
+				if sig == syscall.SIGTERM {
+					_ = cmd.Process.Signal(sig)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	err = cmd.Wait()
+	close(done)
+	if exitErr, ok := errors.AsType[*exec.ExitError](err); ok {
+		code := exitErr.ExitCode()
+		if ws, ok := exitErr.Sys().(syscall.WaitStatus); ok && ws.Signaled() {
+			// Terminated by a signal; report it like a shell does.
+			code = 128 + int(ws.Signal())
+		}
+		if code < 0 {
+			code = 1
+		}
+		return exitCodeError{code: code}
+	}
+	if err != nil {
+		return fmt.Errorf("wait for command: %w", err)
+	}
+	return nil
+}
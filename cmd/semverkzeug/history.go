@@ -0,0 +1,71 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-git/go-git/v5/plumbing"
+
+	"github.com/0x5a17ed/semverkzeug/internal/floatingversion"
+	"github.com/0x5a17ed/semverkzeug/internal/gitrepo"
+)
+
+type historyCmd struct {
+	ScopeArg *gitrepo.Scope `arg:"true" name:"scope" optional:"" help:"tag scope to describe (defaults to scope derived from --repo)"`
+
+	Limit    int  `short:"n" name:"limit" placeholder:"N" help:"stop after N commits (default is all)"`
+	NoPrefix bool `name:"no-prefix" help:"print versions without prefix"`
+}
+
+func (c *historyCmd) Scope() *gitrepo.Scope { return c.ScopeArg }
+
+func (c *historyCmd) Run(ctx context.Context, root *cli, repo *gitrepo.Context, head *plumbing.Reference) error {
+	scope, err := effectiveScope(root, repo, c)
+	if err != nil {
+		return err
+	}
+	if err := checkShallowHistory(ctx, root, repo, head, scope); err != nil {
+		return err
+	}
+
+	entries, doneFn := floatingversion.History(ctx, repo, head, scope)
+
+	n := 0
+	for e := range entries {
+		abbreviatedHash, err := gitrepo.FindUniqueCommitHashAbbreviation(ctx, repo, e.Commit)
+		if err != nil {
+			return fmt.Errorf("abbreviate commit hash: %w", err)
+		}
+
+		version := e.Spec.String()
+		if c.NoPrefix {
+			version = e.Spec.WithScope(gitrepo.RootScope()).WithPrefix("").String()
+		}
+
+		if _, err := fmt.Printf("%s %s\n", abbreviatedHash, version); err != nil {
+			return err
+		}
+
+		if n++; c.Limit > 0 && n >= c.Limit {
+			break
+		}
+	}
+
+	return doneFn()
+}
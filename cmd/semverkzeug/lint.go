@@ -0,0 +1,91 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/0x5a17ed/semverkzeug/internal/gitrepo"
+	"github.com/0x5a17ed/semverkzeug/internal/uiprint"
+)
+
+type lintCmd struct {
+	Strict bool `name:"strict" help:"also fail on warnings"`
+	JSON   bool `name:"json" help:"print the findings as JSON"`
+}
+
+// lintFinding is the JSON output of a finding.
+type lintFinding struct {
+	Check    gitrepo.Check `json:"check"`
+	Severity string        `json:"severity"`
+	Tags     []string      `json:"tags"`
+	Message  string        `json:"message"`
+	Fix      string        `json:"fix"`
+}
+
+func (c *lintCmd) Run(ctx context.Context, repo *gitrepo.Context) error {
+	findings, err := gitrepo.Lint(ctx, repo)
+	if err != nil {
+		return err
+	}
+
+	var errs, warnings int
+	out := []lintFinding{}
+	for _, f := range findings {
+		switch f.Severity {
+		case gitrepo.SeverityError:
+			errs++
+		case gitrepo.SeverityWarning:
+			warnings++
+		}
+		out = append(out, lintFinding{
+			Check:    f.Check,
+			Severity: f.Severity.String(),
+			Tags:     f.Tags,
+			Message:  f.Message,
+			Fix:      f.Fix,
+		})
+	}
+
+	if c.JSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(out); err != nil {
+			return err
+		}
+	} else {
+		for _, f := range findings {
+			if f.Severity == gitrepo.SeverityError {
+				uiprint.Error("%s (%s)", f.Message, f.Check)
+			} else {
+				uiprint.Warning("%s (%s)", f.Message, f.Check)
+			}
+			uiprint.Hint("%s", f.Fix)
+		}
+	}
+
+	if errs > 0 || (c.Strict && warnings > 0) {
+		if c.JSON {
+			return exitCodeError{code: 1}
+		}
+		return fmt.Errorf("found %d error(s) and %d warning(s)", errs, warnings)
+	}
+	return nil
+}
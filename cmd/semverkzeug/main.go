@@ -17,7 +17,11 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"os"
+	"os/signal"
+	"time"
 
 	konghelp "github.com/0x5a17ed/kong-help"
 	"github.com/alecthomas/kong"
@@ -33,6 +37,10 @@ func main() {
 		kong.Description("versioning tool for git repositories"),
 		kong.UsageOnError(),
 	)
+	grammar.configureLogging()
+
+	ctx, cancel := commandContext(grammar.Timeout)
+	kctx.BindTo(ctx, (*context.Context)(nil))
 
 	// Register lazy singleton providers so each command's Run() can
 	// just declare the dependencies it needs (gitrepo.Context, HEAD
@@ -44,8 +52,40 @@ func main() {
 		kctx.FatalIfErrorf(err)
 	}
 
-	if err := kctx.Run(); err != nil {
-		uiprint.Error("%s", err.Error())
+	err := kctx.Run()
+	cancel()
+	if err != nil {
+		// Pass through the exit code of child processes verbatim.
+		if ec, ok := errors.AsType[exitCodeError](err); ok {
+			os.Exit(ec.code)
+		}
+
+		switch {
+		case errors.Is(err, context.DeadlineExceeded):
+			uiprint.Error("timed out after %s: %s", grammar.Timeout, err.Error())
+		case errors.Is(err, context.Canceled):
+			uiprint.Error("interrupted: %s", err.Error())
+			os.Exit(130)
+		default:
+			uiprint.Error("%s", err.Error())
+		}
 		os.Exit(1)
 	}
 }
+
+// commandContext returns the context commands run with.  It is
+// canceled by the first SIGINT, after which a second one terminates
+// the process as usual, and by the end of timeout, unless zero.
+func commandContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	context.AfterFunc(ctx, stop)
+	if timeout <= 0 {
+		return ctx, stop
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	return ctx, func() {
+		cancel()
+		stop()
+	}
+}
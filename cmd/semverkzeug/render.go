@@ -0,0 +1,82 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/go-git/go-git/v5/plumbing"
+
+	"github.com/0x5a17ed/semverkzeug/internal/gitrepo"
+	"github.com/0x5a17ed/semverkzeug/internal/uiprint"
+	"github.com/0x5a17ed/semverkzeug/internal/versioninfo"
+)
+
+type renderCmd struct {
+	Template string `short:"t" name:"template" required:"" type:"existingfile" placeholder:"FILE" help:"Go text/template file to render"`
+	Output   string `short:"o" name:"output" placeholder:"FILE" help:"output file, only rewritten when its content changes (default is stdout)"`
+
+	ScopeFlag *gitrepo.Scope `name:"scope" help:"tag scope to describe (defaults to scope derived from --repo)"`
+
+	AddCommitHash bool `name:"add-commit-hash" help:"add commit hash as metadata"`
+}
+
+func (c *renderCmd) Scope() *gitrepo.Scope { return c.ScopeFlag }
+
+func (c *renderCmd) Run(ctx context.Context, root *cli, repo *gitrepo.Context, head *plumbing.Reference) error {
+	text, err := os.ReadFile(c.Template)
+	if err != nil {
+		return fmt.Errorf("read template: %w", err)
+	}
+
+	scope, err := effectiveScope(root, repo, c)
+	if err != nil {
+		return err
+	}
+	if err := checkShallowHistory(ctx, root, repo, head, scope); err != nil {
+		return err
+	}
+
+	info, err := versioninfo.Collect(ctx, repo, head, scope, versioninfo.Options{
+		AddCommitHash: c.AddCommitHash,
+	})
+	if err != nil {
+		return err
+	}
+
+	out, err := versioninfo.Render(filepath.Base(c.Template), string(text), info)
+	if err != nil {
+		return err
+	}
+
+	if c.Output == "" || c.Output == "-" {
+		_, err = os.Stdout.Write(out)
+		return err
+	}
+
+	changed, err := versioninfo.WriteFileIfChanged(c.Output, out)
+	if err != nil {
+		return err
+	}
+	if changed {
+		uiprint.Step("Rendered %s [%s]", c.Output, info.Version)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+
+	"github.com/0x5a17ed/semverkzeug/internal/bumper"
+	"github.com/0x5a17ed/semverkzeug/internal/gitrepo"
+)
+
+type retagCmd struct {
+	Version string `arg:"" help:"version tag to move, or its version, like v1.2.0 or 1.2.0"`
+	Target  string `arg:"" name:"ref" help:"revision to move the tag to"`
+
+	ScopeFlag *gitrepo.Scope `name:"scope" help:"tag scope of the version (defaults to scope derived from --repo)"`
+	Force     bool           `name:"force" help:"move the tag even if the revision does not descend from the tagged commit"`
+}
+
+func (c *retagCmd) Scope() *gitrepo.Scope { return c.ScopeFlag }
+
+func (c *retagCmd) Run(ctx context.Context, root *cli, repo *gitrepo.Context) error {
+	scope, err := effectiveScope(root, repo, c)
+	if err != nil {
+		return err
+	}
+
+	ref, err := gitrepo.ResolveRef(repo, c.Target)
+	if err != nil {
+		return err
+	}
+
+	_, err = bumper.Retag(ctx, repo, c.Version, ref, scope, c.Force)
+	return err
+}
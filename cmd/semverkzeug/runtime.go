@@ -17,6 +17,7 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -26,10 +27,12 @@ import (
 	"github.com/go-git/go-git/v5/plumbing"
 
 	"github.com/0x5a17ed/semverkzeug/internal/gitrepo"
+	"github.com/0x5a17ed/semverkzeug/internal/uiprint"
 )
 
 // scopeForRepoPath resolves p into a tag scope relative to the
-// repository worktree root.
+// repository worktree root, which is the root of the linked worktree
+// when the repository was opened from within one.
 //
 // It returns the root scope for root-scoped operation, or when no
 // worktree is available.
@@ -85,13 +88,43 @@ func provideRepo(root *cli) (*gitrepo.Context, error) {
 	if err != nil {
 		return nil, fmt.Errorf("create git context: %w", err)
 	}
+
+	policy, err := gitrepo.ParseSubmodulePolicy(root.Submodules)
+	if err != nil {
+		return nil, err
+	}
+	cx.SetSubmodulePolicy(policy)
+	cx.SetCoerceTags(root.CoerceTags)
+	if err := cx.SetMaintenanceBranches(root.MaintenanceBranches); err != nil {
+		return nil, err
+	}
+	if root.BranchLabels {
+		cx.SetBranchLabels(&gitrepo.BranchLabels{
+			DefaultBranches: root.DefaultBranches,
+			MaxLength:       root.BranchLabelMaxLength,
+			Detached:        root.DetachedBranchLabel,
+		})
+	}
+
+	if root.CalVer != "" {
+		scheme, err := gitrepo.ParseCalVer(root.CalVer)
+		if err != nil {
+			return nil, err
+		}
+		cx.SetScheme(scheme)
+	}
 	return cx, nil
 }
 
-// provideHead resolves the repository's HEAD reference.  An empty
-// repository (HEAD missing) is reported as a nil reference rather
-// than an error so commands can decide how to react.
-func provideHead(repo *gitrepo.Context) (*plumbing.Reference, error) {
+// provideHead resolves the reference commands operate on: --ref when
+// given, the repository's HEAD otherwise.  An empty repository (HEAD
+// missing) is reported as a nil reference rather than an error so
+// commands can decide how to react.
+func provideHead(root *cli, repo *gitrepo.Context) (*plumbing.Reference, error) {
+	if root.Ref != "" {
+		return gitrepo.ResolveRef(repo, root.Ref)
+	}
+
 	head, err := repo.Repository().Head()
 	if err != nil && !errors.Is(err, plumbing.ErrReferenceNotFound) {
 		return nil, err
@@ -122,3 +155,38 @@ func effectiveScope(root *cli, repo *gitrepo.Context, overrider scopeProvider) (
 	}
 	return scopeForRepoPath(repo, repoPath)
 }
+
+// checkShallowHistory makes sure a version tag is reachable in a
+// shallow clone, fetching tags and deepening the history as the flags
+// allow.  Otherwise the version silently restarts at the initial one,
+// so this warns, or fails with --fail-on-shallow.
+func checkShallowHistory(ctx context.Context, root *cli, repo *gitrepo.Context, head *plumbing.Reference, scope gitrepo.Scope) error {
+	if head == nil {
+		return nil
+	}
+
+	shallow, err := gitrepo.IsShallow(repo)
+	if err != nil || !shallow {
+		return err
+	}
+
+	guide, err := gitrepo.DeepenUntilTagged(ctx, repo, head, scope, gitrepo.DeepenOptions{
+		FetchTags: root.FetchTags,
+		Deepen:    root.Deepen,
+	})
+	if err != nil {
+		return err
+	}
+	if !guide.IsUnreliable() {
+		return nil
+	}
+
+	const hint = "fetch more history with --fetch-tags --deepen=N, or `git fetch --tags --unshallow`"
+	if root.FailOnShallow {
+		return fmt.Errorf("%w; %s", gitrepo.ErrShallowHistory, hint)
+	}
+
+	uiprint.Warning("%s, the version is likely wrong", gitrepo.ErrShallowHistory)
+	uiprint.Hint(hint)
+	return nil
+}
@@ -0,0 +1,114 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/go-git/go-git/v5/plumbing"
+
+	"github.com/0x5a17ed/semverkzeug/internal/gitrepo"
+	"github.com/0x5a17ed/semverkzeug/internal/stamper"
+	"github.com/0x5a17ed/semverkzeug/internal/uiprint"
+	"github.com/0x5a17ed/semverkzeug/internal/versioninfo"
+)
+
+type stampCmd struct {
+	Files []string `arg:"" name:"file" help:"files to stamp, as PATH or PATH:KEY (KEY is a dot-separated path into JSON, TOML or YAML files)"`
+
+	ScopeFlag *gitrepo.Scope `name:"scope" help:"tag scope to describe (defaults to scope derived from --repo)"`
+	Pattern   *regexp.Regexp `name:"pattern" placeholder:"REGEX" xor:"pattern" help:"regular expression with one capture group used for files of other types"`
+	Template  string         `name:"template" placeholder:"TEXT" xor:"pattern" help:"text with one {{version}} used for files of other types, like APP_VERSION=\"{{version}}\""`
+
+	AddCommitHash bool `name:"add-commit-hash" help:"add commit hash as metadata"`
+	WithPrefix    bool `name:"with-prefix" help:"stamp the version with its prefix"`
+	Check         bool `name:"check" help:"only verify that the files are up to date"`
+}
+
+func (c *stampCmd) Scope() *gitrepo.Scope { return c.ScopeFlag }
+
+func (c *stampCmd) Run(ctx context.Context, root *cli, repo *gitrepo.Context, head *plumbing.Reference) error {
+	pattern := c.Pattern
+	if c.Template != "" {
+		var err error
+		if pattern, err = stamper.CompileTemplate(c.Template); err != nil {
+			return err
+		}
+	}
+
+	// Parse every target up front so a typo doesn't leave the set of
+	// files half stamped.
+	targets := make([]stamper.Target, 0, len(c.Files))
+	for _, f := range c.Files {
+		t, err := stamper.ParseTarget(f, pattern)
+		if err != nil {
+			return err
+		}
+		targets = append(targets, t)
+	}
+
+	scope, err := effectiveScope(root, repo, c)
+	if err != nil {
+		return err
+	}
+	if err := checkShallowHistory(ctx, root, repo, head, scope); err != nil {
+		return err
+	}
+
+	info, err := versioninfo.Collect(ctx, repo, head, scope, versioninfo.Options{
+		AddCommitHash: c.AddCommitHash,
+	})
+	if err != nil {
+		return err
+	}
+
+	version := info.SemVer
+	if c.WithPrefix {
+		version = info.Prefix + version
+	}
+
+	if c.Check {
+		uiprint.Step("Checking files for version [%s]", version)
+	} else {
+		uiprint.Step("Stamping version [%s]", version)
+	}
+
+	outdated := 0
+	for _, t := range targets {
+		changed, err := stamper.Apply(t, version, c.Check)
+		if err != nil {
+			return fmt.Errorf("stamp %s: %w", t.Path, err)
+		}
+
+		switch {
+		case !changed:
+			uiprint.Substep("Up to date: %s", t.Path)
+		case c.Check:
+			uiprint.Substep("Outdated: %s", t.Path)
+			outdated++
+		default:
+			uiprint.Substep("Updated: %s", t.Path)
+		}
+	}
+
+	if outdated > 0 {
+		return fmt.Errorf("%d of %d files: %w", outdated, len(targets), stamper.ErrFileOutdated)
+	}
+	return nil
+}
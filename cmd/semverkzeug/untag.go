@@ -0,0 +1,92 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+
+	"github.com/0x5a17ed/semverkzeug/internal/bumper"
+	"github.com/0x5a17ed/semverkzeug/internal/gitrepo"
+	"github.com/0x5a17ed/semverkzeug/internal/uiprint"
+)
+
+type untagCmd struct {
+	ScopeArg *gitrepo.Scope `arg:"true" name:"scope" optional:"" help:"tag scope to untag (defaults to scope derived from --repo)"`
+
+	Yes    bool   `name:"yes" help:"delete the tag; without it, only show what would be deleted"`
+	Remote string `name:"remote" placeholder:"NAME" help:"also delete the tag from remote NAME, like origin"`
+}
+
+func (c *untagCmd) Scope() *gitrepo.Scope { return c.ScopeArg }
+
+func (c *untagCmd) Run(ctx context.Context, root *cli, repo *gitrepo.Context, head *plumbing.Reference) error {
+	scope, err := effectiveScope(root, repo, c)
+	if err != nil {
+		return err
+	}
+
+	u, err := bumper.PlanUntag(ctx, repo, head, scope)
+	if err != nil {
+		return err
+	}
+
+	commit, err := repo.Repository().CommitObject(u.CommitHash)
+	if err != nil {
+		return fmt.Errorf("resolve commit object: %w", err)
+	}
+	target, err := gitrepo.DescribeCommit(ctx, repo, commit)
+	if err != nil {
+		return err
+	}
+
+	if u.IsAnnotated {
+		uiprint.Step("Annotated tag to delete [%s]", u.TagName)
+	} else {
+		uiprint.Step("Lightweight tag to delete [%s]", u.TagName)
+	}
+	uiprint.Substep("Target: %s", target)
+	if u.Tagger != nil {
+		message, _, _ := strings.Cut(strings.TrimSpace(u.Message), "\n")
+		uiprint.Substep("Tagger: %s, %s", u.Tagger.String(), u.Tagger.When.Format(time.RFC3339))
+		uiprint.Substep("Message: %s", message)
+	}
+	if c.Remote != "" {
+		uiprint.Substep("Remote: %s", c.Remote)
+	}
+
+	if !c.Yes {
+		uiprint.Hint("not deleted, pass --yes to delete it")
+		return nil
+	}
+
+	if err := bumper.DeleteTag(ctx, repo, u, c.Remote); err != nil {
+		return err
+	}
+
+	uiprint.Hint("restore it with the following command(s)")
+	for _, cmd := range u.RestoreCommands(c.Remote) {
+		if _, err := fmt.Println(cmd); err != nil {
+			return err
+		}
+	}
+	return nil
+}
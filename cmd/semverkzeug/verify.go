@@ -0,0 +1,115 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/go-git/go-git/v5/plumbing"
+
+	"github.com/0x5a17ed/semverkzeug/internal/bumper"
+	"github.com/0x5a17ed/semverkzeug/internal/gitrepo"
+	"github.com/0x5a17ed/semverkzeug/internal/uiprint"
+)
+
+// verifyExitCodes maps each rule to the exit code verify fails with
+// when it is the first violated rule, in the order of bumper.Rules.
+// Exit code 1 is left for errors that keep verify from checking.
+var verifyExitCodes = map[bumper.Rule]int{
+	bumper.RuleClean:       3,
+	bumper.RuleTagged:      4,
+	bumper.RuleUnambiguous: 5,
+	bumper.RuleAnnotated:   6,
+	bumper.RuleHighest:     7,
+}
+
+type verifyCmd struct {
+	ScopeFlag *gitrepo.Scope `name:"scope" help:"tag scope to verify (defaults to scope derived from --repo)"`
+
+	RequireAnnotated bool `name:"require-annotated" help:"also fail when the version tag is a lightweight tag"`
+	JSON             bool `name:"json" help:"print the result as JSON"`
+}
+
+func (c *verifyCmd) Scope() *gitrepo.Scope { return c.ScopeFlag }
+
+// verifyResult is the JSON output of verify.
+type verifyResult struct {
+	OK         bool              `json:"ok"`
+	Commit     string            `json:"commit,omitempty"`
+	Tags       []string          `json:"tags"`
+	Violations []verifyViolation `json:"violations"`
+}
+
+type verifyViolation struct {
+	Rule    bumper.Rule `json:"rule"`
+	Message string      `json:"message"`
+}
+
+func (c *verifyCmd) Run(ctx context.Context, root *cli, repo *gitrepo.Context, head *plumbing.Reference) error {
+	scope, err := effectiveScope(root, repo, c)
+	if err != nil {
+		return err
+	}
+	if err := checkShallowHistory(ctx, root, repo, head, scope); err != nil {
+		return err
+	}
+
+	tags, violations, err := bumper.VerifyRelease(ctx, repo, head, scope, bumper.ReleaseOptions{
+		RequireAnnotated: c.RequireAnnotated,
+	})
+	if err != nil {
+		return err
+	}
+
+	res := verifyResult{
+		OK:         len(violations) == 0,
+		Commit:     head.Hash().String(),
+		Tags:       []string{},
+		Violations: []verifyViolation{},
+	}
+	for _, vt := range tags {
+		res.Tags = append(res.Tags, vt.TagName)
+	}
+	for _, v := range violations {
+		res.Violations = append(res.Violations, verifyViolation(v))
+	}
+
+	if c.JSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(res); err != nil {
+			return err
+		}
+	} else {
+		for _, v := range violations {
+			uiprint.Error("%s (%s)", v.Message, v.Rule)
+		}
+		if res.OK {
+			if _, err := fmt.Println(res.Tags[0]); err != nil {
+				return err
+			}
+		}
+	}
+
+	if res.OK {
+		return nil
+	}
+	return exitCodeError{code: verifyExitCodes[violations[0].Rule]}
+}
@@ -17,27 +17,58 @@
 package bumper
 
 import (
+	"time"
+
 	"github.com/Masterminds/semver/v3"
+
+	"github.com/0x5a17ed/semverkzeug/internal/gitrepo"
 )
 
-type partFunc func(semver.Version) semver.Version
+// semVerPart is a part of semantic versions.
+type semVerPart struct {
+	name string
+	inc  func(semver.Version) semver.Version
+}
 
-func (f partFunc) bump(inp semver.Version) semver.Version {
-	return f(inp)
+func (p semVerPart) bump(scheme gitrepo.Scheme, inp semver.Version, _ time.Time) (semver.Version, error) {
+	return scheme.Increment(inp, p.name, p.inc)
 }
 
+// calendarPart advances calendar versions to the release date.
+type calendarPart struct{}
+
+func (calendarPart) bump(scheme gitrepo.Scheme, inp semver.Version, now time.Time) (semver.Version, error) {
+	return scheme.Advance(inp, now)
+}
+
+// Part is a part of a version to bump.  Which parts apply depends on
+// the versioning scheme.
 type Part interface {
-	bump(inp semver.Version) semver.Version
+	bump(scheme gitrepo.Scheme, inp semver.Version, now time.Time) (semver.Version, error)
 }
 
 var (
-	Major Part = partFunc(semver.Version.IncMajor)
-	Minor Part = partFunc(semver.Version.IncMinor)
-	Patch Part = partFunc(semver.Version.IncPatch)
+	Major Part = semVerPart{"major", semver.Version.IncMajor}
+	Minor Part = semVerPart{"minor", semver.Version.IncMinor}
+	Patch Part = semVerPart{"patch", semver.Version.IncPatch}
+
+	// Calendar bumps calendar versions to the date of the release.
+	Calendar Part = calendarPart{}
 )
 
 // Bump calculates a new semantic version by incrementing the
-// specified part of the provided version.
+// specified part of the provided version.  Calendar doesn't apply to
+// semantic versions and leaves ov unchanged.
 func Bump(ov semver.Version, part Part) semver.Version {
-	return part.bump(ov)
+	v, err := part.bump(gitrepo.SemVer, ov, time.Time{})
+	if err != nil {
+		return ov
+	}
+	return v
+}
+
+// BumpScheme calculates the version following ov in scheme when
+// released at now, bumping part.
+func BumpScheme(scheme gitrepo.Scheme, ov semver.Version, part Part, now time.Time) (semver.Version, error) {
+	return part.bump(scheme, ov, now)
 }
@@ -23,4 +23,7 @@ import (
 var (
 	ErrRepositoryIsEmpty = errors.New("repository is empty")
 	ErrRepositoryIsDirty = errors.New("repository contains uncommitted changes")
+	ErrNotTagged         = errors.New("commit has no version tag")
+	ErrNotHighest        = errors.New("not the highest version")
+	ErrNotDescendant     = errors.New("new target does not descend from the tagged commit")
 )
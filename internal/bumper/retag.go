@@ -0,0 +1,173 @@
+/*
+ * Copyright(C) 2022 individual contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package bumper
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+
+	"github.com/0x5a17ed/semverkzeug/internal/gitrepo"
+	"github.com/0x5a17ed/semverkzeug/internal/uiprint"
+)
+
+// PreviousTargetTrailer is the trailer Retag adds to tag messages,
+// naming the commit the tag pointed at before.
+const PreviousTargetTrailer = "Previous-Target"
+
+// Retag moves the version tag of scope naming version, a tag name or
+// a version, to the commit of ref.  The commit must descend from the
+// tagged one unless force is set.  The tag is recreated as an
+// annotated tag, keeping the message and the tagger's name and email
+// of an annotated tag, with the previous commit recorded in a
+// PreviousTargetTrailer.  Like CreateTag, it uses native git when
+// available.
+func Retag(
+	ctx context.Context,
+	cx *gitrepo.Context,
+	version string,
+	ref *plumbing.Reference,
+	scope gitrepo.Scope,
+	force bool,
+) (*plumbing.Reference, error) {
+	vt, err := findVersionTag(ctx, cx, version, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	prevCommit, err := cx.Repository().CommitObject(vt.CommitHash)
+	if err != nil {
+		return nil, fmt.Errorf("resolve commit object: %w", err)
+	}
+	commit, err := cx.Repository().CommitObject(ref.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("resolve commit object: %w", err)
+	}
+	if commit.Hash == prevCommit.Hash {
+		return nil, fmt.Errorf("tag %q already points at %s", vt.TagName, commit.Hash)
+	}
+
+	descendant, err := gitrepo.IsAncestor(ctx, cx, prevCommit.Hash, commit.Hash)
+	if err != nil {
+		return nil, fmt.Errorf("check ancestry: %w", err)
+	}
+	if !descendant && !force {
+		return nil, fmt.Errorf("%s: %w %s of %s; force to move it anyway", commit.Hash, ErrNotDescendant, prevCommit.Hash, vt.TagName)
+	}
+
+	var opts tagOptions
+	message := fmt.Sprintf("retag version %s", vt.TagName)
+	if vt.IsAnnotated {
+		tagRef, err := cx.Repository().Tag(vt.TagName)
+		if err != nil {
+			return nil, fmt.Errorf("resolve tag %q: %w", vt.TagName, err)
+		}
+		tagObj, err := cx.Repository().TagObject(tagRef.Hash())
+		if err != nil {
+			return nil, fmt.Errorf("resolve tag object %s: %w", tagRef.Hash(), err)
+		}
+		message = tagObj.Message
+		opts.Tagger = &tagObj.Tagger
+	}
+	message = addTrailer(message, PreviousTargetTrailer, prevCommit.Hash.String())
+	opts.Force = true
+
+	uiprint.Step("Moving tag [%s]", vt.TagName)
+
+	from, err := gitrepo.DescribeCommit(ctx, cx, prevCommit)
+	if err != nil {
+		return nil, err
+	}
+	to, err := gitrepo.DescribeCommit(ctx, cx, commit)
+	if err != nil {
+		return nil, err
+	}
+	uiprint.Substep("From: %s", from)
+	uiprint.Substep("To: %s", to)
+	if !descendant {
+		uiprint.Warning("%s does not descend from %s, moving the tag rewrites the history of its version", to, from)
+	}
+
+	tagRef, err := createTag(cx, plumbing.NewHashReference(ref.Name(), commit.Hash), vt.TagName, message, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	uiprint.Step("Moved tag [%s]", tagRef.Name().Short())
+	uiprint.Hint("publish it with `git push --force <remote> refs/tags/%s`", vt.TagName)
+	return tagRef, nil
+}
+
+// findVersionTag finds the version tag of scope named version, or
+// naming the version version when there is no such tag.
+func findVersionTag(ctx context.Context, cx *gitrepo.Context, version string, scope gitrepo.Scope) (*gitrepo.VersionTag, error) {
+	versionTags, doneFn := gitrepo.IterVersionTags(ctx, cx, &scope)
+	all := slices.SortedFunc(versionTags, gitrepo.VersionTag.CompareDesc)
+	if err := doneFn(); err != nil {
+		return nil, fmt.Errorf("collect tags: %w", err)
+	}
+
+	if i := slices.IndexFunc(all, func(vt gitrepo.VersionTag) bool { return vt.TagName == version }); i >= 0 {
+		return &all[i], nil
+	}
+
+	vs, err := cx.Scheme().ParseVersionSpec(version)
+	if err != nil {
+		return nil, fmt.Errorf("%#q is neither a version tag nor a version: %w", version, err)
+	}
+
+	var matches []gitrepo.VersionTag
+	for _, vt := range all {
+		if vt.VersionSpec.Version.Equal(&vs.Version) {
+			matches = append(matches, vt)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no version tag of version %s", version)
+	case 1:
+		return &matches[0], nil
+	}
+
+	names := make([]string, 0, len(matches))
+	for _, vt := range matches {
+		names = append(names, vt.TagName)
+	}
+	return nil, fmt.Errorf("version %s is tagged %d times: %s; name the tag to move", version, len(names), strings.Join(names, ", "))
+}
+
+// addTrailer appends the trailer "key: value" to message, after a
+// blank line unless the last paragraph already holds trailers.
+func addTrailer(message, key, value string) string {
+	message = strings.TrimRight(message, "\n")
+	paragraphs := strings.Split(message, "\n\n")
+	last := strings.Split(paragraphs[len(paragraphs)-1], "\n")
+	if len(paragraphs) < 2 || slices.ContainsFunc(last, isNotTrailer) {
+		message += "\n"
+	}
+	return message + "\n" + key + ": " + value + "\n"
+}
+
+// isNotTrailer reports whether line doesn't look like a "Key: value"
+// trailer.
+func isNotTrailer(line string) bool {
+	key, _, ok := strings.Cut(line, ": ")
+	return !ok || key == "" || strings.ContainsAny(key, " \t")
+}
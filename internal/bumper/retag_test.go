@@ -0,0 +1,176 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package bumper_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0x5a17ed/semverkzeug/internal/bumper"
+	"github.com/0x5a17ed/semverkzeug/internal/gitfixture"
+	"github.com/0x5a17ed/semverkzeug/internal/gitrepo"
+)
+
+func TestRetag(t *testing.T) {
+	t.Run("descendant", func(t *testing.T) {
+		// Arrange: an annotated tag, then a fix on top of it.
+		cx := gitfixture.RepoWithOneCommitNoTagsClean(t)
+		first := gitfixture.Head(t, cx).Hash()
+		createAnnotatedTag(t, cx, "v0.1.0", gitfixture.TestSig.When)
+		gitfixture.CommitFile(t, cx, "fix.txt", "fix")
+		head := gitfixture.Head(t, cx)
+
+		gitEnvFixture(t)
+
+		// Act
+		tagRef, err := bumper.Retag(t.Context(), cx, "v0.1.0", head, gitrepo.RootScope(), false)
+
+		// Assert: the tag keeps its message and tagger, and records
+		// where it pointed at before.
+		require.NoError(t, err)
+		tagObj, err := cx.Repository().TagObject(tagRef.Hash())
+		require.NoError(t, err)
+		assert.Equal(t, head.Hash(), tagObj.Target)
+		assert.Equal(t, gitfixture.TestSig.Name, tagObj.Tagger.Name)
+		assert.Equal(t, gitfixture.TestSig.Email, tagObj.Tagger.Email)
+		assert.Equal(t, "release v0.1.0\n\nPrevious-Target: "+first.String()+"\n", tagObj.Message)
+
+		// Act: move it again, by version.
+		gitfixture.CommitFile(t, cx, "fix.txt", "fix again")
+		tagRef, err = bumper.Retag(t.Context(), cx, "0.1.0", gitfixture.Head(t, cx), gitrepo.RootScope(), false)
+
+		// Assert: the trailers accumulate.
+		require.NoError(t, err)
+		tagObj, err = cx.Repository().TagObject(tagRef.Hash())
+		require.NoError(t, err)
+		assert.Equal(t, "release v0.1.0\n\nPrevious-Target: "+first.String()+"\nPrevious-Target: "+head.Hash().String()+"\n", tagObj.Message)
+	})
+
+	t.Run("lightweight", func(t *testing.T) {
+		// Arrange
+		cx := gitfixture.RepoWithOneCommitNoTagsClean(t)
+		first := gitfixture.Head(t, cx).Hash()
+		_, err := cx.Repository().CreateTag("v0.1.0", first, nil)
+		require.NoError(t, err)
+		gitfixture.CommitFile(t, cx, "fix.txt", "fix")
+
+		gitEnvFixture(t)
+
+		// Act
+		tagRef, err := bumper.Retag(t.Context(), cx, "v0.1.0", gitfixture.Head(t, cx), gitrepo.RootScope(), false)
+
+		// Assert: the tag becomes an annotated one.
+		require.NoError(t, err)
+		tagObj, err := cx.Repository().TagObject(tagRef.Hash())
+		require.NoError(t, err)
+		assert.Equal(t, "retag version v0.1.0\n\nPrevious-Target: "+first.String()+"\n", tagObj.Message)
+	})
+
+	t.Run("not-descendant", func(t *testing.T) {
+		// Arrange: the new target is the parent of the tagged commit.
+		cx := gitfixture.RepoWithOneCommitNoTagsClean(t)
+		parent := gitfixture.Head(t, cx)
+		tagged := gitfixture.CommitFile(t, cx, "b.txt", "b").Hash
+		createAnnotatedTag(t, cx, "v0.1.0", gitfixture.TestSig.When)
+
+		gitEnvFixture(t)
+
+		// Act
+		_, err := bumper.Retag(t.Context(), cx, "v0.1.0", parent, gitrepo.RootScope(), false)
+
+		// Assert
+		require.ErrorIs(t, err, bumper.ErrNotDescendant)
+		tagRef, err := cx.Repository().Tag("v0.1.0")
+		require.NoError(t, err)
+		tagObj, err := cx.Repository().TagObject(tagRef.Hash())
+		require.NoError(t, err)
+		assert.Equal(t, tagged, tagObj.Target)
+
+		// Act: force it.
+		tagRef, err = bumper.Retag(t.Context(), cx, "v0.1.0", parent, gitrepo.RootScope(), true)
+
+		// Assert
+		require.NoError(t, err)
+		tagObj, err = cx.Repository().TagObject(tagRef.Hash())
+		require.NoError(t, err)
+		assert.Equal(t, parent.Hash(), tagObj.Target)
+	})
+
+	t.Run("memory", func(t *testing.T) {
+		// Arrange: a repository without storage on disk, which
+		// takes the internal implementation.
+		repo, err := git.Init(memory.NewStorage(), memfs.New())
+		require.NoError(t, err)
+		cx, err := gitrepo.NewContextFromRepo(repo)
+		require.NoError(t, err)
+		first := gitfixture.CommitFile(t, cx, "a.txt", "a")
+		createAnnotatedTag(t, cx, "v0.1.0", gitfixture.TestSig.When)
+		head := gitfixture.CommitFile(t, cx, "fix.txt", "fix")
+
+		// Act
+		tagRef, err := bumper.Retag(t.Context(), cx, "v0.1.0", plumbing.NewHashReference(plumbing.HEAD, head.Hash), gitrepo.RootScope(), false)
+
+		// Assert
+		require.NoError(t, err)
+		tagObj, err := cx.Repository().TagObject(tagRef.Hash())
+		require.NoError(t, err)
+		assert.Equal(t, head.Hash, tagObj.Target)
+		assert.Equal(t, gitfixture.TestSig.Name, tagObj.Tagger.Name)
+		assert.Equal(t, "release v0.1.0\n\nPrevious-Target: "+first.Hash.String()+"\n", tagObj.Message)
+	})
+
+	t.Run("same-commit", func(t *testing.T) {
+		cx := gitfixture.RepoWithOneCommitOneTagClean(t)
+
+		_, err := bumper.Retag(t.Context(), cx, "v0.1.0", gitfixture.Head(t, cx), gitrepo.RootScope(), false)
+		assert.ErrorContains(t, err, "already points at")
+	})
+
+	t.Run("lookup", func(t *testing.T) {
+		// Arrange: two tags of the same version, and a scoped one.
+		cx := gitfixture.RepoWithOneCommitNoTagsClean(t)
+		createAnnotatedTag(t, cx, "v0.1.0", gitfixture.TestSig.When)
+		createAnnotatedTag(t, cx, "0.1.0", gitfixture.TestSig.When.Add(time.Hour))
+		createAnnotatedTag(t, cx, "mod/v1.0.0", gitfixture.TestSig.When)
+		head := plumbing.NewHashReference(plumbing.HEAD, gitfixture.CommitFile(t, cx, "fix.txt", "fix").Hash)
+
+		gitEnvFixture(t)
+		scope, err := gitrepo.ParseScope("mod")
+		require.NoError(t, err)
+
+		// Act & Assert
+		_, err = bumper.Retag(t.Context(), cx, "0.1.0+build", head, gitrepo.RootScope(), false)
+		assert.ErrorContains(t, err, "tagged 2 times")
+
+		_, err = bumper.Retag(t.Context(), cx, "v9.0.0", head, gitrepo.RootScope(), false)
+		assert.ErrorContains(t, err, "no version tag")
+
+		_, err = bumper.Retag(t.Context(), cx, "1.0.0", head, gitrepo.RootScope(), false)
+		assert.ErrorContains(t, err, "no version tag")
+
+		tagRef, err := bumper.Retag(t.Context(), cx, "1.0.0", head, scope, false)
+		require.NoError(t, err)
+		assert.Equal(t, "mod/v1.0.0", tagRef.Name().Short())
+	})
+}
@@ -17,6 +17,7 @@
 package bumper
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -28,6 +29,7 @@ import (
 
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
 
 	"github.com/0x5a17ed/semverkzeug/internal/gitrepo"
 	"github.com/0x5a17ed/semverkzeug/internal/uiprint"
@@ -39,12 +41,13 @@ func hasGitCommand() bool {
 }
 
 func CreateTag(
+	ctx context.Context,
 	cx *gitrepo.Context,
 	ref *plumbing.Reference,
 	part Part,
 	scope gitrepo.Scope,
 ) (*plumbing.Reference, error) {
-	if err := VerifyRepo(cx, ref); err != nil {
+	if err := VerifyRepo(ctx, cx, ref); err != nil {
 		return nil, err
 	}
 
@@ -53,13 +56,29 @@ func CreateTag(
 		return nil, fmt.Errorf("resolve commit object: %w", err)
 	}
 
-	guide, err := gitrepo.BuildGuide(cx, ref, scope)
+	guide, err := gitrepo.BuildGuide(ctx, cx, ref, scope)
 	if err != nil {
 		return nil, fmt.Errorf("build guide: %w", err)
 	}
 
 	currSpec := gitrepo.LatestSpec(guide)
-	nextLabel := currSpec.WithVersion(Bump(currSpec.Version, part)).String()
+	next, err := BumpScheme(cx.Scheme(), currSpec.Version, part, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("bump version %s: %w", currSpec.String(), err)
+	}
+	nextSpec := currSpec.WithVersion(next)
+	nextLabel := nextSpec.String()
+
+	// Maintenance branches only release their own version line.
+	if err := gitrepo.CheckVersionLine(cx, ref, nextSpec); err != nil {
+		return nil, err
+	}
+
+	// The current version may have been coerced from a tag like
+	// "v1.2"; the next one is always a valid version tag.
+	if _, err := cx.Scheme().ParseVersionSpec(nextLabel); err != nil {
+		return nil, fmt.Errorf("next version: %w", err)
+	}
 
 	// Double-check the tag label is not already in use.
 	switch _, err := cx.Repository().Tag(nextLabel); {
@@ -83,36 +102,48 @@ func CreateTag(
 		message = fmt.Sprintf("bump version %s -> %s", currSpec.String(), nextLabel)
 	}
 
-	target, err := gitrepo.FindUniqueCommitHashAbbreviation(cx, commit)
+	target, err := gitrepo.DescribeCommit(ctx, cx, commit)
 	if err != nil {
-		return nil, fmt.Errorf("abbreviate commit hash: %w", err)
-	}
-	subject, _, _ := strings.Cut(commit.Message, "\n")
-	if subject = strings.TrimSpace(subject); subject != "" {
-		target = fmt.Sprintf("%s (%s)", target, subject)
+		return nil, err
 	}
 	uiprint.Substep("Target: %s", target)
 
-	var tagRef *plumbing.Reference
+	tagRef, err := createTag(cx, ref, nextLabel, message, tagOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	uiprint.Step("Created tag [%s]", tagRef.Name().Short())
+	return tagRef, nil
+}
 
+// tagOptions control how createTag creates a tag.
+type tagOptions struct {
+	// Force replaces an existing tag of the same name.
+	Force bool
+
+	// Tagger overrides the name and email of the tagger, which are
+	// the user's when nil.
+	Tagger *object.Signature
+}
+
+// createTag creates an annotated tag with the native git
+// implementation when available, and the internal one otherwise.
+func createTag(
+	cx *gitrepo.Context,
+	ref *plumbing.Reference,
+	label string,
+	message string,
+	opts tagOptions,
+) (*plumbing.Reference, error) {
 	// Check if the repository is backed by a filesystem storage.
 	if p, hasStorage := cx.DotGitPath(); hasStorage && hasGitCommand() {
 		// Use the native git implementation to ensure consistency with other git commands.
-		tagRef, err = createTagNative(cx, ref, nextLabel, message, p)
-		if err != nil {
-			return nil, err
-		}
-
-	} else {
-		// Fall back to tag creation via internal implementation.
-		tagRef, err = createTagInternal(cx, ref, nextLabel, message)
-		if err != nil {
-			return nil, err
-		}
+		return createTagNative(cx, ref, label, message, p, opts)
 	}
 
-	uiprint.Step("Created tag [%s]", tagRef.Name().Short())
-	return tagRef, nil
+	// Fall back to tag creation via internal implementation.
+	return createTagInternal(cx, ref, label, message, opts)
 }
 
 // createTagInternal creates a tag using the internal implementation.
@@ -121,11 +152,23 @@ func createTagInternal(
 	ref *plumbing.Reference,
 	label string,
 	message string,
+	opts tagOptions,
 ) (*plumbing.Reference, error) {
-	// Fall back to tag creation via internal implementation.
-	tagRef, err := cx.Repository().CreateTag(label, ref.Hash(), &git.CreateTagOptions{
+	createOpts := &git.CreateTagOptions{
 		Message: message,
-	})
+	}
+	if opts.Tagger != nil {
+		createOpts.Tagger = &object.Signature{Name: opts.Tagger.Name, Email: opts.Tagger.Email, When: time.Now()}
+	}
+
+	if opts.Force {
+		err := cx.Repository().DeleteTag(label)
+		if err != nil && !errors.Is(err, git.ErrTagNotFound) {
+			return nil, fmt.Errorf("delete tag: %w", err)
+		}
+	}
+
+	tagRef, err := cx.Repository().CreateTag(label, ref.Hash(), createOpts)
 	if err != nil {
 		return nil, fmt.Errorf("create tag: %w", err)
 	}
@@ -140,6 +183,7 @@ func createTagNative(
 	label string,
 	message string,
 	dotGit string,
+	opts tagOptions,
 ) (*plumbing.Reference, error) {
 	wtFs, err := cx.LoadWorktreeFilesystem()
 	if err != nil {
@@ -147,7 +191,11 @@ func createTagNative(
 	}
 
 	// Use the native git implementation to ensure consistency with other git commands.
-	gitArgs := []string{"tag", "-a", "-F", "-", label, ref.Hash().String()}
+	gitArgs := []string{"tag", "-a", "-F", "-"}
+	if opts.Force {
+		gitArgs = append(gitArgs, "-f")
+	}
+	gitArgs = append(gitArgs, label, ref.Hash().String())
 
 	uiprint.Substep("Running: git %s", strings.Join(gitArgs, " "))
 
@@ -157,6 +205,13 @@ func createTagNative(
 		fmt.Sprintf("GIT_DIR=%s", dotGit),
 		fmt.Sprintf("GIT_WORK_TREE=%s", wtFs.Root()),
 	)
+	if opts.Tagger != nil {
+		// git takes the tagger from the committer identity.
+		cmd.Env = append(cmd.Env,
+			fmt.Sprintf("GIT_COMMITTER_NAME=%s", opts.Tagger.Name),
+			fmt.Sprintf("GIT_COMMITTER_EMAIL=%s", opts.Tagger.Email),
+		)
+	}
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
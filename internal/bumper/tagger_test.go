@@ -17,8 +17,10 @@
 package bumper_test
 
 import (
+	"fmt"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/stretchr/testify/assert"
@@ -51,7 +53,7 @@ func TestCreateTag(t *testing.T) {
 		gitEnvFixture(t)
 
 		// Act
-		_, err := bumper.CreateTag(cx, nil, bumper.Patch, gitrepo.RootScope())
+		_, err := bumper.CreateTag(t.Context(), cx, nil, bumper.Patch, gitrepo.RootScope())
 
 		// Assert
 		assert.ErrorIs(t, err, bumper.ErrRepositoryIsEmpty)
@@ -64,7 +66,7 @@ func TestCreateTag(t *testing.T) {
 		gitEnvFixture(t)
 
 		// Act
-		tagRef, err := bumper.CreateTag(cx, gitfixture.Head(t, cx), bumper.Patch, gitrepo.RootScope())
+		tagRef, err := bumper.CreateTag(t.Context(), cx, gitfixture.Head(t, cx), bumper.Patch, gitrepo.RootScope())
 
 		// Assert
 		require.NoError(t, err)
@@ -88,7 +90,7 @@ func TestCreateTag(t *testing.T) {
 		gitEnvFixture(t)
 
 		// Act
-		tagRef, err := bumper.CreateTag(cx, gitfixture.Head(t, cx), bumper.Patch, gitrepo.RootScope())
+		tagRef, err := bumper.CreateTag(t.Context(), cx, gitfixture.Head(t, cx), bumper.Patch, gitrepo.RootScope())
 
 		// Assert
 		require.NoError(t, err)
@@ -105,3 +107,144 @@ func TestCreateTag(t *testing.T) {
 		assert.Equal(t, "bump version v0.1.0 -> v0.1.1\n", tagObject.Message)
 	})
 }
+
+func TestCreateTag_NonHeadRefIgnoresWorktree(t *testing.T) {
+	// Arrange: tag the first commit while HEAD is dirty one commit past it.
+	cx := gitfixture.RepoWithOneCommitNoTagsClean(t)
+	first := gitfixture.Head(t, cx)
+	gitfixture.CommitFile(t, cx, "bar", "baa")
+	gitfixture.WriteRepoFile(t, cx, "bar", "baz")
+
+	gitEnvFixture(t)
+
+	_, err := bumper.CreateTag(t.Context(), cx, gitfixture.Head(t, cx), bumper.Patch, gitrepo.RootScope())
+	require.ErrorIs(t, err, bumper.ErrRepositoryIsDirty)
+
+	// Act
+	tagRef, err := bumper.CreateTag(t.Context(), cx, first, bumper.Patch, gitrepo.RootScope())
+
+	// Assert
+	require.NoError(t, err)
+
+	tagObject, err := cx.Repository().TagObject(tagRef.Hash())
+	require.NoError(t, err)
+	assert.Equal(t, first.Hash(), tagObject.Target)
+}
+
+func TestCreateTag_CoercedTag(t *testing.T) {
+	tests := []struct {
+		coerce bool
+		want   string
+	}{
+		{coerce: false, want: "v0.0.1"},
+		{coerce: true, want: "v1.2.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			// Arrange: a legacy two-component tag one commit back.
+			cx := gitfixture.RepoWithOneCommitNoTagsClean(t)
+			gitfixture.CreateTag(t, cx, "v1.2")
+			gitfixture.CommitFile(t, cx, "bar", "baa")
+			cx.SetCoerceTags(tt.coerce)
+
+			gitEnvFixture(t)
+
+			// Act
+			tagRef, err := bumper.CreateTag(t.Context(), cx, gitfixture.Head(t, cx), bumper.Patch, gitrepo.RootScope())
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, plumbing.NewTagReferenceName(tt.want), tagRef.Name())
+		})
+	}
+}
+
+func TestCreateTag_CalVer(t *testing.T) {
+	scheme, err := gitrepo.ParseCalVer("YYYY.0M.MICRO")
+	require.NoError(t, err)
+
+	year, month, _ := time.Now().UTC().Date()
+	thisMonth := fmt.Sprintf("%04d.%02d", year, month)
+
+	tests := []struct {
+		name string
+		tags []string
+		part bumper.Part
+		want string
+		err  bool
+	}{
+		{name: "first release", part: bumper.Calendar, want: thisMonth + ".0"},
+		{name: "new month resets micro", tags: []string{"2001.02.7"}, part: bumper.Calendar, want: thisMonth + ".0"},
+		{name: "same month counts micro", tags: []string{thisMonth + ".3"}, part: bumper.Calendar, want: thisMonth + ".4"},
+		{name: "semver tags are ignored", tags: []string{"v9.9.9"}, part: bumper.Calendar, want: thisMonth + ".0"},
+		{name: "semantic part", part: bumper.Patch, err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			cx := gitfixture.RepoWithOneCommitNoTagsClean(t)
+			for _, tag := range tt.tags {
+				gitfixture.CreateTag(t, cx, tag)
+			}
+			gitfixture.CommitFile(t, cx, "bar", "baa")
+			cx.SetScheme(scheme)
+
+			gitEnvFixture(t)
+
+			// Act
+			tagRef, err := bumper.CreateTag(t.Context(), cx, gitfixture.Head(t, cx), tt.part, gitrepo.RootScope())
+
+			// Assert
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, plumbing.NewTagReferenceName(tt.want), tagRef.Name())
+		})
+	}
+}
+
+func TestCreateTag_CalendarPartNeedsCalVer(t *testing.T) {
+	cx := gitfixture.RepoWithOneCommitNoTagsClean(t)
+
+	gitEnvFixture(t)
+
+	_, err := bumper.CreateTag(t.Context(), cx, gitfixture.Head(t, cx), bumper.Calendar, gitrepo.RootScope())
+	assert.Error(t, err)
+}
+
+func TestCreateTag_MaintenanceBranch(t *testing.T) {
+	tests := []struct {
+		name string
+		part bumper.Part
+		want string
+	}{
+		{name: "patch", part: bumper.Patch, want: "v1.4.8"},
+		{name: "minor", part: bumper.Minor},
+		{name: "major", part: bumper.Major},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange: one commit past v1.4.7 on release/1.4.
+			cx := gitfixture.RepoWithOneCommitNoTagsClean(t)
+			gitfixture.CreateTag(t, cx, "v1.4.7")
+			gitfixture.Checkout(t, cx, "release/1.4", true)
+			gitfixture.CommitFile(t, cx, "bar", "baa")
+			require.NoError(t, cx.SetMaintenanceBranches([]string{"release/*"}))
+
+			gitEnvFixture(t)
+
+			// Act
+			tagRef, err := bumper.CreateTag(t.Context(), cx, gitfixture.Head(t, cx), tt.part, gitrepo.RootScope())
+
+			// Assert
+			if tt.want == "" {
+				assert.ErrorIs(t, err, gitrepo.ErrOutsideVersionLine)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, plumbing.NewTagReferenceName(tt.want), tagRef.Name())
+		})
+	}
+}
@@ -0,0 +1,169 @@
+/*
+ * Copyright(C) 2022 individual contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package bumper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"github.com/0x5a17ed/semverkzeug/internal/gitrepo"
+	"github.com/0x5a17ed/semverkzeug/internal/uiprint"
+)
+
+// Untag is a version tag to delete, see PlanUntag.
+type Untag struct {
+	gitrepo.VersionTag
+
+	// Target is the object the tag points at: the tag object of
+	// annotated tags, the commit of lightweight ones.
+	Target plumbing.Hash
+
+	// Tagger and Message are those of annotated tags.
+	Tagger  *object.Signature
+	Message string
+}
+
+// PlanUntag finds the version tag of scope on ref that was created
+// last, to undo a bump.  It refuses with ErrNotHighest when scope has
+// a higher version, since deleting the tag would then not restore the
+// version before the bump.
+func PlanUntag(
+	ctx context.Context,
+	cx *gitrepo.Context,
+	ref *plumbing.Reference,
+	scope gitrepo.Scope,
+) (*Untag, error) {
+	if ref == nil {
+		return nil, ErrRepositoryIsEmpty
+	}
+
+	versionTags, doneFn := gitrepo.IterVersionTags(ctx, cx, &scope)
+	all := slices.SortedFunc(versionTags, gitrepo.VersionTag.CompareDesc)
+	if err := doneFn(); err != nil {
+		return nil, fmt.Errorf("collect tags: %w", err)
+	}
+
+	var latest *gitrepo.VersionTag
+	for i, vt := range all {
+		if vt.CommitHash == ref.Hash() && (latest == nil || vt.TagDate.After(latest.TagDate)) {
+			latest = &all[i]
+		}
+	}
+	if latest == nil {
+		return nil, fmt.Errorf("%s: %w", ref.Hash(), ErrNotTagged)
+	}
+	if highest := all[0]; highest.VersionSpec.Version.GreaterThan(&latest.VersionSpec.Version) {
+		return nil, fmt.Errorf("%s is %w of its scope, %s is", latest.TagName, ErrNotHighest, highest.TagName)
+	}
+
+	tagRef, err := cx.Repository().Tag(latest.TagName)
+	if err != nil {
+		return nil, fmt.Errorf("resolve tag %q: %w", latest.TagName, err)
+	}
+
+	u := &Untag{VersionTag: *latest, Target: tagRef.Hash()}
+	if latest.IsAnnotated {
+		tagObj, err := cx.Repository().TagObject(tagRef.Hash())
+		if err != nil {
+			return nil, fmt.Errorf("resolve tag object %s: %w", tagRef.Hash(), err)
+		}
+		u.Tagger = &tagObj.Tagger
+		u.Message = tagObj.Message
+	}
+	return u, nil
+}
+
+// RestoreCommands returns the git commands recreating the tag after
+// DeleteTag, also on remote unless empty.  The tag object of an
+// annotated tag stays in the repository until it is pruned.
+func (u *Untag) RestoreCommands(remote string) []string {
+	cmds := []string{fmt.Sprintf("git tag %s %s", u.TagName, u.Target)}
+	if remote != "" {
+		cmds = append(cmds, fmt.Sprintf("git push %s refs/tags/%s", remote, u.TagName))
+	}
+	return cmds
+}
+
+// DeleteTag deletes the tag of u, first from remote unless empty,
+// so a failing push leaves the local tag in place.
+func DeleteTag(ctx context.Context, cx *gitrepo.Context, u *Untag, remote string) error {
+	uiprint.Step("Deleting tag [%s]", u.TagName)
+
+	if remote != "" {
+		var err error
+		if p, hasStorage := cx.DotGitPath(); hasStorage && hasGitCommand() {
+			err = deleteRemoteTagNative(ctx, u.TagName, remote, p)
+		} else {
+			err = deleteRemoteTagInternal(ctx, cx, u.TagName, remote)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := cx.Repository().DeleteTag(u.TagName); err != nil {
+		return fmt.Errorf("delete tag %q: %w", u.TagName, err)
+	}
+
+	uiprint.Step("Deleted tag [%s]", u.TagName)
+	return nil
+}
+
+// deleteRemoteTagInternal deletes the tag label from remote using the
+// internal implementation.
+func deleteRemoteTagInternal(ctx context.Context, cx *gitrepo.Context, label, remote string) error {
+	refSpec := config.RefSpec(":" + plumbing.NewTagReferenceName(label).String())
+
+	uiprint.Substep("Pushing %s to %s", refSpec, remote)
+
+	err := cx.Repository().PushContext(ctx, &git.PushOptions{
+		RemoteName: remote,
+		RefSpecs:   []config.RefSpec{refSpec},
+	})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return fmt.Errorf("delete tag %q from %s: %w", label, remote, err)
+	}
+	return nil
+}
+
+// deleteRemoteTagNative deletes the tag label from remote using the
+// native git implementation, which knows the configured credentials.
+func deleteRemoteTagNative(ctx context.Context, label, remote, dotGit string) error {
+	gitArgs := []string{"push", remote, ":" + plumbing.NewTagReferenceName(label).String()}
+
+	uiprint.Substep("Running: git %s", strings.Join(gitArgs, " "))
+
+	cmd := exec.CommandContext(ctx, "git", gitArgs...)
+	cmd.Env = append(slices.Clone(os.Environ()), fmt.Sprintf("GIT_DIR=%s", dotGit))
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("delete tag %q from %s: %w", label, remote, err)
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package bumper_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0x5a17ed/semverkzeug/internal/bumper"
+	"github.com/0x5a17ed/semverkzeug/internal/gitfixture"
+	"github.com/0x5a17ed/semverkzeug/internal/gitrepo"
+)
+
+func createAnnotatedTag(t *testing.T, cx *gitrepo.Context, name string, when time.Time) {
+	t.Helper()
+
+	_, err := cx.Repository().CreateTag(name, gitfixture.Head(t, cx).Hash(), &git.CreateTagOptions{
+		Tagger:  &object.Signature{Name: gitfixture.TestSig.Name, Email: gitfixture.TestSig.Email, When: when},
+		Message: "release " + name,
+	})
+	require.NoError(t, err)
+}
+
+func TestPlanUntag(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		cx := gitfixture.RepoEmpty(t)
+
+		_, err := bumper.PlanUntag(t.Context(), cx, nil, gitrepo.RootScope())
+		assert.ErrorIs(t, err, bumper.ErrRepositoryIsEmpty)
+	})
+
+	t.Run("untagged", func(t *testing.T) {
+		cx := gitfixture.RepoWithTwoCommitsOneTagClean(t)
+
+		_, err := bumper.PlanUntag(t.Context(), cx, gitfixture.Head(t, cx), gitrepo.RootScope())
+		assert.ErrorIs(t, err, bumper.ErrNotTagged)
+	})
+
+	t.Run("created-last", func(t *testing.T) {
+		// Arrange: an older tag of a higher version, then a newer one
+		// naming the same version on the same commit.
+		cx := gitfixture.RepoWithOneCommitNoTagsClean(t)
+		createAnnotatedTag(t, cx, "v0.1.0", gitfixture.TestSig.When)
+		createAnnotatedTag(t, cx, "v0.2.0", gitfixture.TestSig.When.Add(time.Hour))
+		createAnnotatedTag(t, cx, "0.2.0", gitfixture.TestSig.When.Add(2*time.Hour))
+
+		// Act
+		u, err := bumper.PlanUntag(t.Context(), cx, gitfixture.Head(t, cx), gitrepo.RootScope())
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, "0.2.0", u.TagName)
+		require.NotNil(t, u.Tagger)
+		assert.Equal(t, "release 0.2.0\n", u.Message)
+
+		tagRef, err := cx.Repository().Tag("0.2.0")
+		require.NoError(t, err)
+		assert.Equal(t, tagRef.Hash(), u.Target)
+	})
+
+	t.Run("not-highest", func(t *testing.T) {
+		// Arrange: the commit's tag is lower than one created earlier.
+		cx := gitfixture.RepoWithOneCommitNoTagsClean(t)
+		createAnnotatedTag(t, cx, "v1.0.0", gitfixture.TestSig.When)
+		gitfixture.CommitFile(t, cx, "b.txt", "b")
+		createAnnotatedTag(t, cx, "v0.9.0", gitfixture.TestSig.When.Add(time.Hour))
+
+		// Act
+		_, err := bumper.PlanUntag(t.Context(), cx, gitfixture.Head(t, cx), gitrepo.RootScope())
+
+		// Assert
+		assert.ErrorIs(t, err, bumper.ErrNotHighest)
+	})
+
+	t.Run("other-scope", func(t *testing.T) {
+		// Arrange: a higher version in another scope doesn't matter.
+		cx := gitfixture.RepoWithOneCommitNoTagsClean(t)
+		createAnnotatedTag(t, cx, "mod/v2.0.0", gitfixture.TestSig.When)
+		createAnnotatedTag(t, cx, "v0.1.0", gitfixture.TestSig.When)
+
+		// Act
+		u, err := bumper.PlanUntag(t.Context(), cx, gitfixture.Head(t, cx), gitrepo.RootScope())
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, "v0.1.0", u.TagName)
+	})
+}
+
+func TestDeleteTag(t *testing.T) {
+	gitfixture.RequireGitCommand(t)
+	gitEnvFixture(t)
+
+	// Arrange: a released tag, pushed to a remote.
+	cx := gitfixture.RepoWithOneCommitNoTagsClean(t)
+	createAnnotatedTag(t, cx, "v0.1.0", gitfixture.TestSig.When)
+
+	remotePath := filepath.Join(t.TempDir(), "remote.git")
+	remote, err := git.PlainInit(remotePath, true)
+	require.NoError(t, err)
+	_, err = cx.Repository().CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{remotePath}})
+	require.NoError(t, err)
+	require.NoError(t, cx.Repository().Push(&git.PushOptions{
+		RemoteName: "origin",
+		RefSpecs:   []config.RefSpec{"refs/tags/*:refs/tags/*"},
+	}))
+
+	u, err := bumper.PlanUntag(t.Context(), cx, gitfixture.Head(t, cx), gitrepo.RootScope())
+	require.NoError(t, err)
+
+	// Act
+	err = bumper.DeleteTag(t.Context(), cx, u, "origin")
+
+	// Assert: the tag is gone locally and on the remote.
+	require.NoError(t, err)
+	_, err = cx.Repository().Tag("v0.1.0")
+	assert.ErrorIs(t, err, git.ErrTagNotFound)
+	_, err = remote.Tag("v0.1.0")
+	assert.ErrorIs(t, err, git.ErrTagNotFound)
+
+	// Act: restore it.
+	cmds := u.RestoreCommands("origin")
+	require.Len(t, cmds, 2)
+	assert.Equal(t, "git tag v0.1.0 "+u.Target.String(), cmds[0])
+	assert.Equal(t, "git push origin refs/tags/v0.1.0", cmds[1])
+	gitfixture.RunGit(t, gitfixture.Filesystem(t, cx).Root(), "tag", "v0.1.0", u.Target.String())
+
+	// Assert: the original tag object is back.
+	tagRef, err := cx.Repository().Tag("v0.1.0")
+	require.NoError(t, err)
+	assert.Equal(t, u.Target, tagRef.Hash())
+}
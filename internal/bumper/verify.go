@@ -17,7 +17,11 @@
 package bumper
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/go-git/go-git/v5/plumbing"
 
@@ -25,13 +29,22 @@ import (
 )
 
 // VerifyRepo validates the repository state by checking the reference and
-// ensuring a clean working tree status.
-func VerifyRepo(cx *gitrepo.Context, ref *plumbing.Reference) error {
+// ensuring a clean working tree status.  The working tree is only
+// checked when ref points at the checked-out commit.
+func VerifyRepo(ctx context.Context, cx *gitrepo.Context, ref *plumbing.Reference) error {
 	if ref == nil {
 		return ErrRepositoryIsEmpty
 	}
 
-	st, err := gitrepo.BuildWorktreeStatus(cx)
+	atHead, err := gitrepo.IsHead(cx, ref.Hash())
+	if err != nil {
+		return err
+	}
+	if !atHead {
+		return nil
+	}
+
+	st, err := gitrepo.BuildWorktreeStatus(ctx, cx)
 	if err != nil {
 		return fmt.Errorf("read worktree status: %w", err)
 	}
@@ -42,3 +55,137 @@ func VerifyRepo(cx *gitrepo.Context, ref *plumbing.Reference) error {
 
 	return nil
 }
+
+// Rule names a requirement VerifyRelease checks.
+type Rule string
+
+const (
+	// RuleClean requires a worktree without uncommitted changes.
+	RuleClean Rule = "clean"
+
+	// RuleTagged requires a version tag on the commit itself.
+	RuleTagged Rule = "tagged"
+
+	// RuleUnambiguous requires a single version tag on the commit,
+	// rather than several naming the same version, like "v1.0.0" and
+	// "1.0.0".
+	RuleUnambiguous Rule = "unambiguous"
+
+	// RuleAnnotated requires the version tag to be an annotated tag.
+	// Only checked with ReleaseOptions.RequireAnnotated.
+	RuleAnnotated Rule = "annotated"
+
+	// RuleHighest requires the version to be the highest of all
+	// version tags in the scope.
+	RuleHighest Rule = "highest"
+)
+
+// Rules lists the rules in the order VerifyRelease checks them.
+var Rules = []Rule{RuleClean, RuleTagged, RuleUnambiguous, RuleAnnotated, RuleHighest}
+
+// Violation is a rule a release does not satisfy.
+type Violation struct {
+	Rule    Rule
+	Message string
+}
+
+// ReleaseOptions control which rules VerifyRelease checks.
+type ReleaseOptions struct {
+	// RequireAnnotated enables RuleAnnotated.
+	RequireAnnotated bool
+}
+
+// VerifyRelease checks whether ref is exactly a release of scope.  It
+// returns the version tags of scope on ref, highest first, and the
+// violated rules in the order of Rules, or none.  The rules about the
+// version tag are only checked when ref is tagged.
+func VerifyRelease(
+	ctx context.Context,
+	cx *gitrepo.Context,
+	ref *plumbing.Reference,
+	scope gitrepo.Scope,
+	opts ReleaseOptions,
+) ([]gitrepo.VersionTag, []Violation, error) {
+	var violations []Violation
+
+	switch err := VerifyRepo(ctx, cx, ref); {
+	case errors.Is(err, ErrRepositoryIsDirty):
+		violations = append(violations, Violation{Rule: RuleClean, Message: err.Error()})
+	case err != nil:
+		return nil, nil, err
+	}
+
+	commit, err := cx.Repository().CommitObject(ref.Hash())
+	if err != nil {
+		return nil, nil, fmt.Errorf("resolve commit object: %w", err)
+	}
+
+	versionTags, doneFn := gitrepo.IterVersionTags(ctx, cx, &scope)
+	all := slices.SortedFunc(versionTags, gitrepo.VersionTag.CompareDesc)
+	if err := doneFn(); err != nil {
+		return nil, nil, fmt.Errorf("collect tags: %w", err)
+	}
+
+	var tags []gitrepo.VersionTag
+	for _, vt := range all {
+		if vt.CommitHash == commit.Hash {
+			tags = append(tags, vt)
+		}
+	}
+
+	if len(tags) == 0 {
+		message, err := describeUntagged(ctx, cx, ref, scope)
+		if err != nil {
+			return nil, nil, err
+		}
+		violations = append(violations, Violation{Rule: RuleTagged, Message: message})
+		return nil, violations, nil
+	}
+
+	names := make([]string, 0, len(tags))
+	var lightweight []string
+	for _, vt := range tags {
+		names = append(names, vt.TagName)
+		if !vt.IsAnnotated {
+			lightweight = append(lightweight, vt.TagName)
+		}
+	}
+
+	if len(names) > 1 {
+		violations = append(violations, Violation{
+			Rule:    RuleUnambiguous,
+			Message: fmt.Sprintf("commit is tagged with conflicting versions %s", strings.Join(names, ", ")),
+		})
+	}
+
+	if opts.RequireAnnotated && len(lightweight) > 0 {
+		violations = append(violations, Violation{
+			Rule:    RuleAnnotated,
+			Message: fmt.Sprintf("version tag %s is not annotated", strings.Join(lightweight, ", ")),
+		})
+	}
+
+	if highest := all[0]; highest.VersionSpec.Version.GreaterThan(&tags[0].VersionSpec.Version) {
+		violations = append(violations, Violation{
+			Rule:    RuleHighest,
+			Message: fmt.Sprintf("version %s is lower than %s", tags[0].TagName, highest.TagName),
+		})
+	}
+
+	return tags, violations, nil
+}
+
+// describeUntagged explains that ref is not tagged, naming the
+// version it is past if there is one.
+func describeUntagged(ctx context.Context, cx *gitrepo.Context, ref *plumbing.Reference, scope gitrepo.Scope) (string, error) {
+	guide, err := gitrepo.BuildGuide(ctx, cx, ref, scope)
+	if err != nil {
+		return "", fmt.Errorf("build guide: %w", err)
+	}
+
+	const message = "commit is not tagged with a version"
+	if vt := guide.HighestVersion(); vt != nil {
+		return fmt.Sprintf("%s, it is %d commit(s) past %s", message, guide.Depth, vt.TagName), nil
+	}
+	return message, nil
+}
@@ -0,0 +1,155 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package bumper_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0x5a17ed/semverkzeug/internal/bumper"
+	"github.com/0x5a17ed/semverkzeug/internal/gitfixture"
+	"github.com/0x5a17ed/semverkzeug/internal/gitrepo"
+)
+
+func TestVerifyRelease(t *testing.T) {
+	lightweight := func(t *testing.T, cx *gitrepo.Context, name string) {
+		t.Helper()
+		_, err := cx.Repository().CreateTag(name, gitfixture.Head(t, cx).Hash(), nil)
+		require.NoError(t, err)
+	}
+
+	tests := []struct {
+		name     string
+		arrange  func(t *testing.T) *gitrepo.Context
+		scope    string
+		opts     bumper.ReleaseOptions
+		wantTags []string
+		want     []bumper.Rule
+	}{
+		{
+			name:     "release",
+			arrange:  gitfixture.RepoWithOneCommitOneTagClean,
+			wantTags: []string{"v0.1.0"},
+		},
+		{
+			name:     "dirty",
+			arrange:  gitfixture.RepoWithOneCommitOneTagDirty,
+			wantTags: []string{"v0.1.0"},
+			want:     []bumper.Rule{bumper.RuleClean},
+		},
+		{
+			name:    "untagged",
+			arrange: gitfixture.RepoWithTwoCommitsOneTagClean,
+			want:    []bumper.Rule{bumper.RuleTagged},
+		},
+		{
+			name:    "dirty-and-untagged",
+			arrange: gitfixture.RepoWithTwoCommitsOneTagDirty,
+			want:    []bumper.Rule{bumper.RuleClean, bumper.RuleTagged},
+		},
+		{
+			name: "conflicting",
+			arrange: func(t *testing.T) *gitrepo.Context {
+				cx := gitfixture.RepoWithOneCommitOneTagClean(t)
+				gitfixture.CreateTag(t, cx, "0.1.0")
+				return cx
+			},
+			wantTags: []string{"v0.1.0", "0.1.0"},
+			want:     []bumper.Rule{bumper.RuleUnambiguous},
+		},
+		{
+			name: "lightweight",
+			arrange: func(t *testing.T) *gitrepo.Context {
+				cx := gitfixture.RepoWithOneCommitNoTagsClean(t)
+				lightweight(t, cx, "v0.1.0")
+				return cx
+			},
+			wantTags: []string{"v0.1.0"},
+		},
+		{
+			name: "lightweight-required-annotated",
+			arrange: func(t *testing.T) *gitrepo.Context {
+				cx := gitfixture.RepoWithOneCommitNoTagsClean(t)
+				lightweight(t, cx, "v0.1.0")
+				return cx
+			},
+			opts:     bumper.ReleaseOptions{RequireAnnotated: true},
+			wantTags: []string{"v0.1.0"},
+			want:     []bumper.Rule{bumper.RuleAnnotated},
+		},
+		{
+			name: "not-highest",
+			arrange: func(t *testing.T) *gitrepo.Context {
+				cx := gitfixture.RepoWithOneCommitNoTagsClean(t)
+				gitfixture.CreateTag(t, cx, "v1.0.0")
+				gitfixture.CommitFile(t, cx, "bar", "baa")
+				gitfixture.CreateTag(t, cx, "v0.9.1")
+				return cx
+			},
+			wantTags: []string{"v0.9.1"},
+			want:     []bumper.Rule{bumper.RuleHighest},
+		},
+		{
+			name: "highest-in-scope",
+			arrange: func(t *testing.T) *gitrepo.Context {
+				cx := gitfixture.RepoWithOneCommitNoTagsClean(t)
+				gitfixture.CreateTag(t, cx, "v1.0.0")
+				gitfixture.CommitFile(t, cx, "bar", "baa")
+				gitfixture.CreateTag(t, cx, "mod/v0.9.1")
+				return cx
+			},
+			scope:    "mod",
+			wantTags: []string{"mod/v0.9.1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			cx := tt.arrange(t)
+			scope, err := gitrepo.ParseScope(tt.scope)
+			require.NoError(t, err)
+
+			// Act
+			tags, violations, err := bumper.VerifyRelease(t.Context(), cx, gitfixture.Head(t, cx), scope, tt.opts)
+			require.NoError(t, err)
+
+			// Assert
+			var gotTags []string
+			for _, vt := range tags {
+				gotTags = append(gotTags, vt.TagName)
+			}
+			assert.Equal(t, tt.wantTags, gotTags)
+
+			var got []bumper.Rule
+			for _, v := range violations {
+				assert.NotEmpty(t, v.Message)
+				got = append(got, v.Rule)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		cx := gitfixture.RepoEmpty(t)
+
+		_, _, err := bumper.VerifyRelease(t.Context(), cx, nil, gitrepo.RootScope(), bumper.ReleaseOptions{})
+
+		assert.ErrorIs(t, err, bumper.ErrRepositoryIsEmpty)
+	})
+}
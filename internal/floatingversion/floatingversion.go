@@ -17,12 +17,14 @@
 package floatingversion
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"regexp"
 	"time"
 
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
 
 	"github.com/0x5a17ed/semverkzeug/internal/gitrepo"
 )
@@ -57,29 +59,150 @@ func formatMTime(t *time.Time) string {
 	)
 }
 
+// Result is the outcome of resolving a floating version.
+type Result struct {
+	// Spec is the floating version.
+	Spec gitrepo.VersionSpec
+
+	// Dirty reports whether the worktree contains uncommitted changes.
+	Dirty bool
+
+	// MTime is the timestamp the dev label was derived from.  Nil when
+	// Spec is a released version or the repository has no commits.
+	MTime *time.Time
+}
+
 // Describe returns a floating version string for the given reference.
 func Describe(
+	ctx context.Context,
 	cx *gitrepo.Context,
 	guide *gitrepo.Guide,
 ) (gitrepo.VersionSpec, error) {
-	mtime, err := gitrepo.FindStableWorktreeMTime(cx)
+	r, err := Resolve(ctx, cx, guide)
+	if err != nil {
+		return gitrepo.VersionSpec{}, err
+	}
+	return r.Spec, nil
+}
+
+// Resolve works like Describe but also reports the worktree state
+// the floating version was derived from.  The worktree and the branch
+// checked out are only consulted when guide describes the checked-out
+// commit; any other commit is resolved as if by ResolveCommitted.
+// Dev versions of the checked-out commit carry the branch identifier
+// of gitrepo.BranchLabel before their dev label, like
+// "v1.2.4-feat-login.dev.<mtime>".
+func Resolve(
+	ctx context.Context,
+	cx *gitrepo.Context,
+	guide *gitrepo.Guide,
+) (Result, error) {
+	ex, err := Explain(ctx, cx, guide)
+	if err != nil {
+		return Result{}, err
+	}
+	return ex.Result, nil
+}
+
+// Explanation is how Resolve arrived at a floating version.
+type Explanation struct {
+	Result
+
+	// Base is the version the floating version derives from, see
+	// gitrepo.LatestSpec.
+	Base gitrepo.VersionSpec
+
+	// AtHead reports whether the guide describes the checked-out
+	// commit, so the worktree and branch were consulted.
+	AtHead bool
+
+	// Worktree is how the worktree mtime was found.  Nil for other
+	// commits, clean worktrees and bare repositories.
+	Worktree *gitrepo.WorktreeMTime
+
+	// PatchBumped reports whether Base is no prerelease, so its patch
+	// version was bumped.
+	PatchBumped bool
+
+	// DevLabel is the dev label composed of Branch, the branch
+	// identifier, and the mtime; upsertDev put it into the prerelease
+	// label of Base, replacing its dev label when DevReplaced.  Empty
+	// when Spec is Base, a released version.
+	DevLabel    string
+	Branch      string
+	DevReplaced bool
+}
+
+// Explain works like Resolve but also reports how it arrived at the
+// floating version.
+func Explain(
+	ctx context.Context,
+	cx *gitrepo.Context,
+	guide *gitrepo.Guide,
+) (*Explanation, error) {
+	var head plumbing.Hash
+	if guide.HasCommit() {
+		head = guide.Commit.Hash
+	}
+	atHead, err := gitrepo.IsHead(cx, head)
+	if err != nil {
+		return nil, err
+	}
+	if !atHead {
+		return resolve(guide, nil, "")
+	}
+
+	branch, err := gitrepo.BranchLabel(cx)
+	if err != nil {
+		return nil, fmt.Errorf("branch label: %w", err)
+	}
+
+	var mtime *time.Time
+	wt, err := gitrepo.ExplainStableWorktreeMTime(ctx, cx)
 	switch {
 	case errors.Is(err, git.ErrIsBareRepository):
 		err = nil // Ignore.
 	case errors.Is(err, gitrepo.ErrWorktreeClean):
 		err = nil // Ignore.
 	case err != nil:
-		return gitrepo.VersionSpec{}, fmt.Errorf("find worktree mtime: %w", err)
+		return nil, fmt.Errorf("find worktree mtime: %w", err)
 	default:
-		// Fall through.
+		mtime = &wt.Emitted
 	}
 
+	ex, err := resolve(guide, mtime, branch)
+	if err != nil {
+		return nil, err
+	}
+	ex.AtHead = true
+	ex.Worktree = wt
+	return ex, nil
+}
+
+// ResolveCommitted resolves the floating version of the guide's
+// commit as if the worktree were clean, deriving dev labels from
+// the commit timestamp.
+func ResolveCommitted(guide *gitrepo.Guide) (Result, error) {
+	ex, err := resolve(guide, nil, "")
+	if err != nil {
+		return Result{}, err
+	}
+	return ex.Result, nil
+}
+
+// resolve derives the floating version from guide and the worktree
+// mtime, nil for a clean worktree.  Dev versions get the identifier
+// branch before their dev label, unless empty.
+func resolve(guide *gitrepo.Guide, mtime *time.Time, branch string) (*Explanation, error) {
+	dirty := mtime != nil
 	spec := gitrepo.LatestSpec(guide)
+	ex := &Explanation{Base: spec, Branch: branch}
 
 	if mtime == nil {
 		// Return the latest version if there are no changes since the last tag.
 		if guide.IsPure() {
-			return spec, nil
+			ex.Result = Result{Spec: spec}
+			return ex, nil
 		}
 
 		// Try filling mtime from the timestamp of the last commit.
@@ -95,17 +218,25 @@ func Describe(
 	// Bump the patch version if the spec is not a pre-release yet.
 	if prereleaseLabel == "" {
 		spec.Version = spec.Version.IncPatch()
+		ex.PatchBumped = true
 	}
 
 	// Set the prerelease version to "dev" and the timestamp.
 	newDevLabel := fmt.Sprintf("dev.%s", formatMTime(mtime))
+	if branch != "" {
+		newDevLabel = branch + "." + newDevLabel
+	}
+	ex.DevLabel = newDevLabel
+	ex.DevReplaced = devLabelRegexp.MatchString(prereleaseLabel)
 
 	prereleaseLabel = upsertDev(prereleaseLabel, newDevLabel)
 
+	var err error
 	spec.Version, err = spec.Version.SetPrerelease(prereleaseLabel)
 	if err != nil {
-		return gitrepo.VersionSpec{}, fmt.Errorf("set prerelease: %w", err)
+		return nil, fmt.Errorf("set prerelease: %w", err)
 	}
 
-	return spec, nil
+	ex.Result = Result{Spec: spec, Dirty: dirty, MTime: mtime}
+	return ex, nil
 }
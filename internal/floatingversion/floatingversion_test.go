@@ -18,6 +18,7 @@ package floatingversion_test
 
 import (
 	"errors"
+	"path/filepath"
 	"testing"
 
 	"github.com/Masterminds/semver/v3"
@@ -62,11 +63,11 @@ func TestDescribe(t *testing.T) {
 				require.NoError(t, err)
 			}
 
-			guide, err := gitrepo.BuildGuide(cx, head, gitrepo.RootScope())
+			guide, err := gitrepo.BuildGuide(t.Context(), cx, head, gitrepo.RootScope())
 			require.NoError(t, err)
 
 			// Act: Describe the floating version based on the guide.
-			gotVs, err := floatingversion.Describe(cx, guide)
+			gotVs, err := floatingversion.Describe(t.Context(), cx, guide)
 			require.NoError(t, err)
 
 			// Assert: The floating version matches the expected pattern.
@@ -93,13 +94,13 @@ func TestDescribe_DirtyPrereleaseTagMustSortAbove(t *testing.T) {
 	head, err := cx.Repository().Head()
 	require.NoError(t, err)
 
-	guide, err := gitrepo.BuildGuide(cx, head, gitrepo.RootScope())
+	guide, err := gitrepo.BuildGuide(t.Context(), cx, head, gitrepo.RootScope())
 	require.NoError(t, err)
 
 	srcTag := semver.MustParse("1.0.0-rc.1")
 
 	// Act: Describe the floating version on top of the dirty rc.
-	gotVs, err := floatingversion.Describe(cx, guide)
+	gotVs, err := floatingversion.Describe(t.Context(), cx, guide)
 	require.NoError(t, err)
 
 	// Assert: The dev snapshot must sort strictly above the source
@@ -145,11 +146,11 @@ func TestDescribe_DirtyDevPrereleaseReplacesCounter(t *testing.T) {
 			head, err := cx.Repository().Head()
 			require.NoError(t, err)
 
-			guide, err := gitrepo.BuildGuide(cx, head, gitrepo.RootScope())
+			guide, err := gitrepo.BuildGuide(t.Context(), cx, head, gitrepo.RootScope())
 			require.NoError(t, err)
 
 			// Act: Describe the floating version on top of the dirty tag.
-			gotVs, err := floatingversion.Describe(cx, guide)
+			gotVs, err := floatingversion.Describe(t.Context(), cx, guide)
 			require.NoError(t, err)
 
 			// Assert: The existing dev.<n> counter is replaced (not
@@ -159,3 +160,258 @@ func TestDescribe_DirtyDevPrereleaseReplacesCounter(t *testing.T) {
 		})
 	}
 }
+
+// TestDescribe_CalVer checks that calendar versions get dev labels
+// like semantic versions, sorting after the last release.
+func TestDescribe_CalVer(t *testing.T) {
+	tests := []struct {
+		format, tag, wantRegex string
+	}{
+		{"YYYY.0M.MICRO", "2026.10.3", `^2026\.10\.4-dev\.\d{6}T\d{8}Z$`},
+		{"YY.MM", "26.10", `^26\.10\.1-dev\.\d{6}T\d{8}Z$`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			// Arrange: one commit past the release, next to a semver tag.
+			scheme, err := gitrepo.ParseCalVer(tt.format)
+			require.NoError(t, err)
+
+			cx := gitfixture.RepoEmpty(t)
+			gitfixture.CommitFile(t, cx, "foo", "baa")
+			gitfixture.CreateTag(t, cx, tt.tag)
+			gitfixture.CreateTag(t, cx, "v9.9.9")
+			gitfixture.CommitFile(t, cx, "foo", "baz")
+			cx.SetScheme(scheme)
+
+			guide, err := gitrepo.BuildGuide(t.Context(), cx, gitfixture.Head(t, cx), gitrepo.RootScope())
+			require.NoError(t, err)
+
+			// Act
+			gotVs, err := floatingversion.Describe(t.Context(), cx, guide)
+			require.NoError(t, err)
+
+			// Assert
+			assert.Regexp(t, tt.wantRegex, gotVs.String())
+
+			release, err := scheme.ParseVersionSpec(tt.tag)
+			require.NoError(t, err)
+			assert.True(t, gotVs.Version.GreaterThan(&release.Version))
+		})
+	}
+}
+
+// TestDescribe_BranchLabels checks that dev versions of feature
+// branches name the branch and sort by it, while the default branch
+// and other commits keep the plain dev label.
+func TestDescribe_BranchLabels(t *testing.T) {
+	// Arrange: a feature branch one commit past v1.0.0.
+	cx := gitfixture.RepoEmpty(t)
+	gitfixture.CommitFile(t, cx, "foo", "baa")
+	gitfixture.CreateTag(t, cx, "v1.0.0")
+	gitfixture.Checkout(t, cx, "feat/login", true)
+	gitfixture.CommitFile(t, cx, "foo", "baz")
+	cx.SetBranchLabels(&gitrepo.BranchLabels{DefaultBranches: []string{"main"}})
+
+	describe := func(ref *plumbing.Reference) string {
+		t.Helper()
+		guide, err := gitrepo.BuildGuide(t.Context(), cx, ref, gitrepo.RootScope())
+		require.NoError(t, err)
+		gotVs, err := floatingversion.Describe(t.Context(), cx, guide)
+		require.NoError(t, err)
+		return gotVs.String()
+	}
+
+	// Act & Assert
+	featureHead := gitfixture.Head(t, cx)
+	feature := describe(featureHead)
+	assert.Regexp(t, `^v1\.0\.1-feat-login\.dev\.\d{6}T\d{8}Z$`, feature)
+
+	gitfixture.Checkout(t, cx, "main", false)
+	gitfixture.CommitFile(t, cx, "bar", "baz")
+	main := describe(gitfixture.Head(t, cx))
+	assert.Regexp(t, `^v1\.0\.1-dev\.\d{6}T\d{8}Z$`, main)
+
+	// The branch checked out doesn't name other commits.
+	assert.Regexp(t, `^v1\.0\.1-dev\.\d{6}T\d{8}Z$`, describe(featureHead))
+
+	featureVs, err := gitrepo.ParseVersionSpec(feature)
+	require.NoError(t, err)
+	mainVs, err := gitrepo.ParseVersionSpec(main)
+	require.NoError(t, err)
+	assert.True(t, featureVs.Version.GreaterThan(&mainVs.Version))
+}
+
+func TestResolve_Dirty(t *testing.T) {
+	tests := []struct {
+		name      string
+		repo      repositoryProvider
+		wantDirty bool
+	}{
+		{"one-tag-clean", gitfixture.RepoWithOneCommitOneTagClean, false},
+		{"one-tag-dirty", gitfixture.RepoWithOneCommitOneTagDirty, true},
+		{"one-commit-past-one-tag-clean", gitfixture.RepoWithTwoCommitsOneTagClean, false},
+		{"one-commit-past-one-tag-dirty", gitfixture.RepoWithTwoCommitsOneTagDirty, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			cx := tt.repo(t)
+
+			guide, err := gitrepo.BuildGuide(t.Context(), cx, gitfixture.Head(t, cx), gitrepo.RootScope())
+			require.NoError(t, err)
+
+			// Act
+			got, err := floatingversion.Resolve(t.Context(), cx, guide)
+			require.NoError(t, err)
+
+			// Assert
+			assert.Equal(t, tt.wantDirty, got.Dirty)
+			assert.Equal(t, guide.IsPure() && !tt.wantDirty, got.MTime == nil)
+		})
+	}
+}
+
+// TestResolve_NonHeadIgnoresWorktree verifies that a dirty worktree
+// only affects the checked-out commit; describing an older commit
+// yields the same clean-tree result as ResolveCommitted.
+func TestResolve_NonHeadIgnoresWorktree(t *testing.T) {
+	// Arrange: HEAD is one commit past the tagged commit, and dirty.
+	cx := gitfixture.RepoWithTwoCommitsOneTagDirty(t)
+
+	tagRef, err := gitrepo.ResolveRef(cx, "v0.1.0")
+	require.NoError(t, err)
+
+	guide, err := gitrepo.BuildGuide(t.Context(), cx, tagRef, gitrepo.RootScope())
+	require.NoError(t, err)
+
+	// Act
+	got, err := floatingversion.Resolve(t.Context(), cx, guide)
+	require.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, "v0.1.0", got.Spec.String())
+	assert.False(t, got.Dirty)
+}
+
+func TestExplain(t *testing.T) {
+	// Arrange: a dirty worktree on top of a prerelease tag.
+	cx := gitfixture.RepoEmpty(t)
+	gitfixture.CommitFile(t, cx, "foo", "baa")
+	gitfixture.CreateTag(t, cx, "v1.0.0-alpha.dev.3")
+	gitfixture.WriteRepoFile(t, cx, "foo", "baz")
+
+	guide, err := gitrepo.BuildGuide(t.Context(), cx, gitfixture.Head(t, cx), gitrepo.RootScope())
+	require.NoError(t, err)
+
+	// Act
+	got, err := floatingversion.Explain(t.Context(), cx, guide)
+	require.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, "v1.0.0-alpha.dev.3", got.Base.String())
+	assert.True(t, got.AtHead)
+	require.NotNil(t, got.Worktree)
+	assert.True(t, got.Worktree.Emitted.Equal(*got.MTime))
+	assert.False(t, got.PatchBumped)
+	assert.True(t, got.DevReplaced)
+	assert.Equal(t, "v1.0.0-alpha."+got.DevLabel, got.Spec.String())
+}
+
+func TestHistory(t *testing.T) {
+	// Arrange: A [v0.1.0] -- B -- C [v0.2.0-rc.1] -- D (dirty)
+	cx := gitfixture.RepoWithTwoCommitsOneTagClean(t)
+	gitfixture.CommitFile(t, cx, "baz", "baa")
+	gitfixture.CreateTag(t, cx, "v0.2.0-rc.1")
+	gitfixture.CommitFile(t, cx, "qux", "baa")
+	gitfixture.WriteRepoFile(t, cx, "qux", "dirty")
+
+	// Act
+	entries, doneFn := floatingversion.History(t.Context(), cx, gitfixture.Head(t, cx), gitrepo.RootScope())
+
+	var got []string
+	for e := range entries {
+		got = append(got, e.Spec.String())
+	}
+	require.NoError(t, doneFn())
+
+	// Assert: dev labels derive from the fixed commit timestamp, the
+	// dirty worktree is ignored.
+	assert.Equal(t, []string{
+		"v0.2.0-rc.1.dev.240101T00000000Z",
+		"v0.2.0-rc.1",
+		"v0.1.1-dev.240101T00000000Z",
+		"v0.1.0",
+	}, got)
+}
+
+// TestHistory_CalVer checks that history entries keep the format of
+// calendar versions, also without scope and prefix.
+func TestHistory_CalVer(t *testing.T) {
+	tests := []struct {
+		format, tag string
+		want        []string
+	}{
+		{"YYYY.0M.MICRO", "v2026.01.3", []string{"2026.01.4-dev.240101T00000000Z", "2026.01.3"}},
+		{"YY.MM", "v26.10", []string{"26.10.1-dev.240101T00000000Z", "26.10"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			// Arrange: A [tag] -- B
+			scheme, err := gitrepo.ParseCalVer(tt.format)
+			require.NoError(t, err)
+
+			cx := gitfixture.RepoEmpty(t)
+			gitfixture.CommitFile(t, cx, "foo", "baa")
+			gitfixture.CreateTag(t, cx, tt.tag)
+			gitfixture.CommitFile(t, cx, "foo", "baz")
+			cx.SetScheme(scheme)
+
+			// Act
+			entries, doneFn := floatingversion.History(t.Context(), cx, gitfixture.Head(t, cx), gitrepo.RootScope())
+
+			var got []string
+			for e := range entries {
+				got = append(got, e.Spec.WithScope(gitrepo.RootScope()).WithPrefix("").String())
+			}
+			require.NoError(t, doneFn())
+
+			// Assert
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestHistory_ShallowClone(t *testing.T) {
+	_ = gitfixture.NewHomeFixture(t)
+	gitfixture.IsolateGitConfig(t)
+
+	// Arrange: Only the tip of A [v0.1.0] -- B -- C is cloned.
+	src := t.TempDir()
+	gitfixture.RunGit(t, src, "init", "-q", "-b", "main")
+	for _, name := range []string{"foo", "bar", "baz"} {
+		gitfixture.WriteFile(t, filepath.Join(src, name), "baa")
+		gitfixture.RunGit(t, src, "add", ".")
+		gitfixture.RunGit(t, src, "commit", "-q", "-m", name)
+		if name == "foo" {
+			gitfixture.RunGit(t, src, "tag", "-a", "-m", "v0.1.0", "v0.1.0")
+		}
+	}
+
+	dst := filepath.Join(t.TempDir(), "clone")
+	gitfixture.RunGit(t, src, "clone", "-q", "--depth=1", "--no-tags", "file://"+src, dst)
+	cx, err := gitrepo.NewContextFromPath(dst)
+	require.NoError(t, err)
+
+	// Act
+	entries, doneFn := floatingversion.History(t.Context(), cx, gitfixture.Head(t, cx), gitrepo.RootScope())
+
+	var got []string
+	for e := range entries {
+		got = append(got, e.Spec.String())
+	}
+
+	// Assert: The walk ends at the shallow boundary instead of failing
+	// on the missing parent.
+	require.NoError(t, doneFn())
+	assert.Len(t, got, 1)
+}
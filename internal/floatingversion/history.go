@@ -0,0 +1,101 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package floatingversion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+
+	"github.com/0x5a17ed/xit"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"github.com/0x5a17ed/semverkzeug/internal/gitrepo"
+)
+
+// HistoryEntry is the version a commit would have been described as.
+type HistoryEntry struct {
+	// Commit is the described commit.
+	Commit *object.Commit
+
+	// Guide is the guide the version was derived from.
+	Guide *gitrepo.Guide
+
+	// Spec is the floating version of Commit.
+	Spec gitrepo.VersionSpec
+}
+
+// History walks the first-parent history starting at ref, newest
+// first, and yields the version each commit resolves to as if it
+// were checked out with a clean worktree.  All commits share one
+// GuideBuilder, so the repository's tags are collected only once.
+func History(ctx context.Context, cx *gitrepo.Context, ref *plumbing.Reference, scope gitrepo.Scope) (iter.Seq[HistoryEntry], func() error) {
+	return xit.Perform(func(yield func(HistoryEntry) bool) error {
+		if ref == nil {
+			return nil
+		}
+
+		b, err := gitrepo.NewGuideBuilder(ctx, cx, scope)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = b.Close() }()
+
+		commit, err := cx.Repository().CommitObject(ref.Hash())
+		if err != nil {
+			return fmt.Errorf("resolve commit object: %w", err)
+		}
+
+		for commit != nil {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			guide, err := b.Build(plumbing.NewHashReference(ref.Name(), commit.Hash))
+			if err != nil {
+				return fmt.Errorf("build guide for %s: %w", commit.Hash, err)
+			}
+
+			res, err := ResolveCommitted(guide)
+			if err != nil {
+				return fmt.Errorf("resolve %s: %w", commit.Hash, err)
+			}
+
+			if !yield(HistoryEntry{Commit: commit, Guide: guide, Spec: res.Spec}) {
+				return nil
+			}
+
+			// The parents of a shallow clone's boundary were not
+			// fetched.
+			if b.IsShallowBoundary(commit.Hash) {
+				break
+			}
+
+			commit, err = commit.Parent(0)
+			switch {
+			case errors.Is(err, object.ErrParentNotFound):
+				commit = nil
+			case err != nil:
+				return fmt.Errorf("resolve first parent: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
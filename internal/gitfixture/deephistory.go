@@ -0,0 +1,173 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package gitfixture
+
+import (
+	"fmt"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	commitgraphfmt "github.com/go-git/go-git/v5/plumbing/format/commitgraph/v2"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0x5a17ed/semverkzeug/internal/gitrepo"
+)
+
+// RepoWithDeepHistory creates a repository whose main branch holds
+// depth commits, written straight into the object store so that
+// histories of thousands of commits stay cheap to set up.  Every
+// seventh commit merges a one-commit side branch, and every tagEvery
+// commits on main are tagged v0.<n>.0, alternating between annotated
+// and lightweight tags.  All commits share the empty tree.
+func RepoWithDeepHistory(tb testing.TB, depth, tagEvery int) *gitrepo.Context {
+	tb.Helper()
+
+	cx := newRepo(tb)
+	repo := cx.Repository()
+
+	treeHash := storeObject(tb, repo.Storer, &object.Tree{})
+
+	var parent plumbing.Hash
+	for i := 1; i <= depth; i++ {
+		var parents []plumbing.Hash
+		if !parent.IsZero() {
+			parents = append(parents, parent)
+		}
+
+		if i%7 == 0 && !parent.IsZero() {
+			side := storeCommit(tb, repo.Storer, treeHash, fmt.Sprintf("side %d", i), i, parent)
+			parents = append(parents, side)
+		}
+
+		parent = storeCommit(tb, repo.Storer, treeHash, fmt.Sprintf("commit %d", i), i, parents...)
+
+		if tagEvery > 0 && i%tagEvery == 0 {
+			n := i / tagEvery
+			var opts *git.CreateTagOptions
+			if n%2 == 1 {
+				opts = &git.CreateTagOptions{Tagger: TestSig, Message: "tagged commit"}
+			}
+			_, err := repo.CreateTag(fmt.Sprintf("v0.%d.0", n), parent, opts)
+			require.NoError(tb, err)
+		}
+	}
+
+	if !parent.IsZero() {
+		require.NoError(tb, repo.Storer.SetReference(plumbing.NewHashReference(plumbing.Main, parent)))
+	}
+
+	return cx
+}
+
+func storeObject(tb testing.TB, s storer.EncodedObjectStorer, obj interface {
+	Encode(plumbing.EncodedObject) error
+}) plumbing.Hash {
+	tb.Helper()
+
+	eo := s.NewEncodedObject()
+	require.NoError(tb, obj.Encode(eo))
+
+	h, err := s.SetEncodedObject(eo)
+	require.NoError(tb, err)
+
+	return h
+}
+
+func storeCommit(tb testing.TB, s storer.EncodedObjectStorer, tree plumbing.Hash, msg string, seq int, parents ...plumbing.Hash) plumbing.Hash {
+	tb.Helper()
+
+	sig := *TestSig
+	sig.When = sig.When.Add(time.Duration(seq) * time.Second)
+
+	return storeObject(tb, s, &object.Commit{
+		Author:       sig,
+		Committer:    sig,
+		Message:      msg,
+		TreeHash:     tree,
+		ParentHashes: parents,
+	})
+}
+
+// WriteCommitGraph writes a commit-graph file covering every commit
+// currently in the repository, like `git commit-graph write
+// --reachable` would.  Commits created afterwards are not part of it.
+func WriteCommitGraph(tb testing.TB, cx *gitrepo.Context) {
+	tb.Helper()
+
+	repo := cx.Repository()
+
+	commits := map[plumbing.Hash]*object.Commit{}
+	iter, err := repo.CommitObjects()
+	require.NoError(tb, err)
+	require.NoError(tb, iter.ForEach(func(c *object.Commit) error {
+		commits[c.Hash] = c
+		return nil
+	}))
+
+	// Topological levels, resolved parents-first without recursion.
+	generations := map[plumbing.Hash]uint64{}
+	for h := range commits {
+		stack := []plumbing.Hash{h}
+		for len(stack) > 0 {
+			top := stack[len(stack)-1]
+			if _, ok := generations[top]; ok {
+				stack = stack[:len(stack)-1]
+				continue
+			}
+
+			var gen uint64
+			pending := false
+			for _, p := range commits[top].ParentHashes {
+				pg, ok := generations[p]
+				if !ok {
+					stack = append(stack, p)
+					pending = true
+					continue
+				}
+				gen = max(gen, pg)
+			}
+			if !pending {
+				generations[top] = gen + 1
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+
+	idx := commitgraphfmt.NewMemoryIndex()
+	for h, c := range commits {
+		idx.Add(h, &commitgraphfmt.CommitData{
+			TreeHash:     c.TreeHash,
+			ParentHashes: c.ParentHashes,
+			Generation:   generations[h],
+			When:         c.Committer.When,
+		})
+	}
+
+	fsys := cx.DotGitFilesystem()
+	require.NotNil(tb, fsys)
+
+	f, err := fsys.Create(path.Join("objects", "info", "commit-graph"))
+	require.NoError(tb, err)
+	defer func() { require.NoError(tb, f.Close()) }()
+
+	require.NoError(tb, commitgraphfmt.NewEncoder(f).Encode(idx))
+}
@@ -0,0 +1,65 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package gitfixture
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// RequireGitCommand skips the test when native git is not installed.
+func RequireGitCommand(t *testing.T) {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git command not available")
+	}
+}
+
+// RunGit runs native git in dir, isolated from the user's and the
+// system's configuration, and returns its trimmed standard output.
+func RunGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	RequireGitCommand(t)
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_CONFIG_GLOBAL=/dev/null",
+		"GIT_CONFIG_NOSYSTEM=1",
+		"GIT_AUTHOR_NAME="+TestSig.Name,
+		"GIT_AUTHOR_EMAIL="+TestSig.Email,
+		"GIT_AUTHOR_DATE="+TestSig.When.Format(time.RFC3339),
+		"GIT_COMMITTER_NAME="+TestSig.Name,
+		"GIT_COMMITTER_EMAIL="+TestSig.Email,
+		"GIT_COMMITTER_DATE="+TestSig.When.Format(time.RFC3339),
+	)
+
+	out, err := cmd.Output()
+	if exitErr, ok := errors.AsType[*exec.ExitError](err); ok {
+		require.NoError(t, err, "git %s: %s", strings.Join(args, " "), exitErr.Stderr)
+	}
+	require.NoError(t, err, "git %s", strings.Join(args, " "))
+
+	return strings.TrimSpace(string(out))
+}
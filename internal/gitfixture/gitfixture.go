@@ -104,7 +104,7 @@ func Checkout(t *testing.T, cx *gitrepo.Context, name string, create bool) {
 	}))
 }
 
-func Head(t *testing.T, cx *gitrepo.Context) *plumbing.Reference {
+func Head(t testing.TB, cx *gitrepo.Context) *plumbing.Reference {
 	t.Helper()
 
 	ref, err := cx.Repository().Head()
@@ -128,7 +128,13 @@ func CreateTag(t *testing.T, cx *gitrepo.Context, name string) plumbing.Hash {
 func RepoEmpty(t *testing.T) *gitrepo.Context {
 	t.Helper()
 
-	repoPath := filepath.Join(t.TempDir(), "repo")
+	return newRepo(t)
+}
+
+func newRepo(tb testing.TB) *gitrepo.Context {
+	tb.Helper()
+
+	repoPath := filepath.Join(tb.TempDir(), "repo")
 
 	repo, err := git.PlainInitWithOptions(repoPath, &git.PlainInitOptions{
 		InitOptions: git.InitOptions{
@@ -136,10 +142,10 @@ func RepoEmpty(t *testing.T) *gitrepo.Context {
 		},
 		ObjectFormat: config.SHA1,
 	})
-	require.NoError(t, err)
+	require.NoError(tb, err)
 
 	cx, err := gitrepo.NewContextFromRepo(repo)
-	require.NoError(t, err)
+	require.NoError(tb, err)
 
 	return cx
 }
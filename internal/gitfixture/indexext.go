@@ -0,0 +1,175 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package gitfixture
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0x5a17ed/semverkzeug/internal/gitrepo"
+)
+
+// AppendIndexExtension adds an extension to the repository's index
+// file, the way git stores data go-git does not write itself.
+func AppendIndexExtension(t *testing.T, cx *gitrepo.Context, signature string, payload []byte) {
+	t.Helper()
+
+	dotGit, ok := cx.DotGitPath()
+	require.True(t, ok)
+	p := filepath.Join(dotGit, "index")
+
+	data, err := os.ReadFile(p)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	buf.Write(data[:len(data)-sha1.Size])
+	buf.WriteString(signature)
+	_ = binary.Write(&buf, binary.BigEndian, uint32(len(payload)))
+	buf.Write(payload)
+
+	sum := sha1.Sum(buf.Bytes())
+	buf.Write(sum[:])
+
+	fi, err := os.Stat(p)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(p, buf.Bytes(), 0o644))
+	require.NoError(t, os.Chtimes(p, fi.ModTime(), fi.ModTime()))
+}
+
+// EWAHBitmap encodes a bitmap of size bits with the given bits set
+// in git's on-disk EWAH format, using literal words only.
+func EWAHBitmap(size int, set ...int) []byte {
+	words := make([]uint64, (size+63)/64)
+	for _, i := range set {
+		words[i/64] |= 1 << (i % 64)
+	}
+
+	var buf bytes.Buffer
+	_ = binary.Write(&buf, binary.BigEndian, uint32(size))
+	_ = binary.Write(&buf, binary.BigEndian, uint32(len(words)+1))
+	_ = binary.Write(&buf, binary.BigEndian, uint64(len(words))<<33)
+	_ = binary.Write(&buf, binary.BigEndian, words)
+	_ = binary.Write(&buf, binary.BigEndian, uint32(0))
+	return buf.Bytes()
+}
+
+// FSMonitorExtension encodes a version 2 fsmonitor (FSMN) extension
+// for an index with entries entries, of which dirty are not known to
+// be unchanged.
+func FSMonitorExtension(token string, entries int, dirty ...int) []byte {
+	bitmap := EWAHBitmap(entries, dirty...)
+
+	var buf bytes.Buffer
+	_ = binary.Write(&buf, binary.BigEndian, uint32(2))
+	buf.WriteString(token)
+	buf.WriteByte(0)
+	_ = binary.Write(&buf, binary.BigEndian, uint32(len(bitmap)))
+	buf.Write(bitmap)
+	return buf.Bytes()
+}
+
+// UntrackedCacheDir describes one directory of an untracked cache.
+type UntrackedCacheDir struct {
+	Name      string
+	Untracked []string
+	Dirs      []UntrackedCacheDir
+}
+
+// UntrackedCacheExtension encodes an untracked cache (UNTR) extension
+// describing root, recording the current mtime and .gitignore of
+// every directory as git would.  No global exclude files may exist.
+func UntrackedCacheExtension(t *testing.T, cx *gitrepo.Context, root UntrackedCacheDir) []byte {
+	t.Helper()
+
+	worktreeRoot, err := cx.LoadWorktreeRoot()
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	writeVarint := func(v int) {
+		// Values used here stay below 128.
+		require.Less(t, v, 128)
+		buf.WriteByte(byte(v))
+	}
+
+	ident := "Location " + worktreeRoot + ", system test\x00"
+	writeVarint(len(ident))
+	buf.WriteString(ident)
+	buf.Write(make([]byte, 2*36+4))                   // exclude file stat data, dir flags
+	buf.Write(make([]byte, 2*len(plumbing.ZeroHash))) // no info/exclude, no excludesFile
+	buf.WriteString(".gitignore\x00")
+
+	var dirs []string
+
+	var writeDir func(d UntrackedCacheDir, p string)
+	writeDir = func(d UntrackedCacheDir, p string) {
+		dirs = append(dirs, p)
+		writeVarint(len(d.Untracked))
+		writeVarint(len(d.Dirs))
+		buf.WriteString(d.Name + "\x00")
+		for _, u := range d.Untracked {
+			buf.WriteString(u + "\x00")
+		}
+		for _, sub := range d.Dirs {
+			writeDir(sub, filepath.Join(p, sub.Name))
+		}
+	}
+
+	writeVarint(1)
+	writeDir(root, worktreeRoot)
+
+	all := make([]int, len(dirs))
+	for i := range all {
+		all[i] = i
+	}
+	// Directories without a .gitignore carry no exclude hash.
+	var hashed []int
+	var hashes []plumbing.Hash
+	for i, d := range dirs {
+		if content, err := os.ReadFile(filepath.Join(d, ".gitignore")); err == nil {
+			// git hashes the file with a newline appended.
+			hashed = append(hashed, i)
+			hashes = append(hashes, plumbing.ComputeHash(plumbing.BlobObject, append(content, '\n')))
+		}
+	}
+
+	buf.Write(EWAHBitmap(len(dirs), all...))    // valid
+	buf.Write(EWAHBitmap(len(dirs)))            // check only
+	buf.Write(EWAHBitmap(len(dirs), hashed...)) // exclude hash valid
+
+	for _, d := range dirs {
+		fi, err := os.Stat(d)
+		require.NoError(t, err)
+
+		stat := make([]byte, 36)
+		binary.BigEndian.PutUint32(stat[8:], uint32(fi.ModTime().Unix()))
+		binary.BigEndian.PutUint32(stat[12:], uint32(fi.ModTime().Nanosecond()))
+		buf.Write(stat)
+	}
+	for _, h := range hashes {
+		buf.Write(h[:])
+	}
+	buf.WriteByte(0)
+
+	return buf.Bytes()
+}
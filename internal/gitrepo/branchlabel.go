@@ -0,0 +1,106 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package gitrepo
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// BranchLabels configures the branch identifiers of dev versions.
+type BranchLabels struct {
+	// DefaultBranches are the branches whose dev versions have no
+	// branch identifier, like "main".
+	DefaultBranches []string
+
+	// MaxLength caps the length of branch identifiers; zero means no
+	// limit.
+	MaxLength int
+
+	// Detached is the identifier used when HEAD is detached, empty for
+	// none.
+	Detached string
+}
+
+// SetBranchLabels sets how dev versions of the checked-out branch are
+// labeled.  Nil, the default, disables branch identifiers.
+func (cx *Context) SetBranchLabels(l *BranchLabels) {
+	cx.branchLabels = l
+}
+
+// currentBranch returns the short name of the branch checked out at
+// HEAD, or false when HEAD is detached.
+func currentBranch(cx *Context) (string, bool, error) {
+	head, err := cx.Repository().Reference(plumbing.HEAD, false)
+	if err != nil {
+		return "", false, fmt.Errorf("resolve HEAD: %w", err)
+	}
+	if head.Type() != plumbing.SymbolicReference || !head.Target().IsBranch() {
+		return "", false, nil
+	}
+	return head.Target().Short(), true, nil
+}
+
+// BranchLabel returns the prerelease identifier naming the branch
+// checked out at HEAD, as configured by Context.SetBranchLabels.  It's
+// empty when branch labels are disabled, on a default branch, and
+// when HEAD is detached without a fallback identifier.
+func BranchLabel(cx *Context) (string, error) {
+	l := cx.branchLabels
+	if l == nil {
+		return "", nil
+	}
+
+	branch, ok, err := currentBranch(cx)
+	switch {
+	case err != nil:
+		return "", err
+	case !ok:
+		return SanitizeLabel(l.Detached, l.MaxLength), nil
+	case slices.Contains(l.DefaultBranches, branch):
+		return "", nil
+	}
+	return SanitizeLabel(branch, l.MaxLength), nil
+}
+
+var labelInvalidRegExp = regexp.MustCompile(`[^0-9a-z-]+`)
+
+// SanitizeLabel turns s into a prerelease identifier of at most
+// maxLength characters, unless maxLength is zero: lower case letters,
+// digits and dashes, with every other run of characters replaced by a
+// single dash, like "feat-login" for "feat/Login".  Identifiers of
+// digits only are prefixed with "branch-", since semantic versions
+// order numeric identifiers differently.
+func SanitizeLabel(s string, maxLength int) string {
+	truncate := func(s string) string {
+		if maxLength > 0 && len(s) > maxLength {
+			s = strings.TrimRight(s[:maxLength], "-")
+		}
+		return s
+	}
+
+	s = labelInvalidRegExp.ReplaceAllString(strings.ToLower(s), "-")
+	s = truncate(strings.Trim(s, "-"))
+	if s != "" && strings.Trim(s, "0123456789") == "" {
+		s = truncate("branch-" + s)
+	}
+	return s
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package gitrepo_test
+
+import (
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0x5a17ed/semverkzeug/internal/gitfixture"
+	"github.com/0x5a17ed/semverkzeug/internal/gitrepo"
+)
+
+func TestSanitizeLabel(t *testing.T) {
+	tests := []struct {
+		input     string
+		maxLength int
+		want      string
+	}{
+		{input: "feat/login", want: "feat-login"},
+		{input: "Feat/Login_Page", want: "feat-login-page"},
+		{input: "--a//b..c--", want: "a-b-c"},
+		{input: "feat/login-page", maxLength: 11, want: "feat-login"},
+		{input: "123", want: "branch-123"},
+		{input: "0123-fix", maxLength: 4, want: "bran"},
+		{input: "007", maxLength: 10, want: "branch-007"},
+		{input: "ümlaut", want: "mlaut"},
+		{input: "///", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.want, gitrepo.SanitizeLabel(tt.input, tt.maxLength))
+		})
+	}
+}
+
+func TestBranchLabel(t *testing.T) {
+	labels := &gitrepo.BranchLabels{DefaultBranches: []string{"main"}, MaxLength: 10}
+
+	tests := []struct {
+		name   string
+		branch string
+		labels *gitrepo.BranchLabels
+		want   string
+	}{
+		{name: "disabled", branch: "feat/login"},
+		{name: "default branch", branch: "main", labels: labels},
+		{name: "feature branch", branch: "feat/login", labels: labels, want: "feat-login"},
+		{name: "long branch", branch: "feat/login-page", labels: labels, want: "feat-login"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cx := gitfixture.RepoWithOneCommitNoTagsClean(t)
+			if tt.branch != "main" {
+				gitfixture.Checkout(t, cx, tt.branch, true)
+			}
+			cx.SetBranchLabels(tt.labels)
+
+			got, err := gitrepo.BranchLabel(cx)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("detached", func(t *testing.T) {
+		cx := gitfixture.RepoWithOneCommitNoTagsClean(t)
+		head := gitfixture.Head(t, cx)
+		require.NoError(t, gitfixture.Worktree(t, cx).Checkout(&git.CheckoutOptions{Hash: head.Hash()}))
+
+		cx.SetBranchLabels(&gitrepo.BranchLabels{})
+		got, err := gitrepo.BranchLabel(cx)
+		require.NoError(t, err)
+		assert.Empty(t, got)
+
+		cx.SetBranchLabels(&gitrepo.BranchLabels{Detached: "CI build"})
+		got, err = gitrepo.BranchLabel(cx)
+		require.NoError(t, err)
+		assert.Equal(t, "ci-build", got)
+	})
+}
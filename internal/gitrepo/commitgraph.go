@@ -0,0 +1,420 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package gitrepo
+
+import (
+	"bytes"
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	commitgraphfmt "github.com/go-git/go-git/v5/plumbing/format/commitgraph/v2"
+	"github.com/go-git/go-git/v5/plumbing/object/commitgraph"
+)
+
+// graphNode is the reduced view of a commit needed for reachability
+// questions.
+type graphNode struct {
+	hash    plumbing.Hash
+	parents []plumbing.Hash
+
+	// generation is the commit's topological level: 1 for root
+	// commits, one more than the highest parent otherwise.  A commit
+	// can only reach commits of a strictly lower generation, which
+	// lets walks stop early.  Zero while not yet known.
+	generation uint64
+}
+
+// commitGraph answers reachability questions over the commit history.
+//
+// When the repository carries a commit-graph file
+// (.git/objects/info/commit-graph or a split commit-graph chain),
+// parents and generation numbers are read from it without
+// inflating commit objects.  Commits missing from the file, or
+// every commit when there is no file, fall back to the object
+// store and have their generation computed on first use.
+type commitGraph struct {
+	// ctx cancels walks; it is checked whenever a commit is loaded.
+	ctx context.Context
+
+	index  commitgraph.CommitNodeIndex
+	closer io.Closer
+	nodes  map[plumbing.Hash]*graphNode
+
+	// shallow holds the boundary commits of a shallow clone, whose
+	// parents were not fetched.
+	shallow map[plumbing.Hash]bool
+}
+
+// newCommitGraph opens the commit-graph of the repository if there
+// is one.  A missing or unreadable commit-graph file is not an error;
+// it is an optimization only.
+func newCommitGraph(ctx context.Context, cx *Context) *commitGraph {
+	g := &commitGraph{
+		ctx:     ctx,
+		nodes:   map[plumbing.Hash]*graphNode{},
+		shallow: map[plumbing.Hash]bool{},
+	}
+
+	storer := cx.Repository().Storer
+	if hashes, err := storer.Shallow(); err == nil {
+		for _, h := range hashes {
+			g.shallow[h] = true
+		}
+	}
+
+	if fsys := cx.DotGitFilesystem(); fsys != nil {
+		if idx, err := commitgraphfmt.OpenChainOrFileIndex(fsys); err == nil {
+			g.index = commitgraph.NewGraphCommitNodeIndex(idx, storer)
+			g.closer = idx
+		}
+	}
+	if g.index == nil {
+		g.index = commitgraph.NewObjectCommitNodeIndex(storer)
+	}
+
+	return g
+}
+
+// hasFile reports whether the graph is backed by a commit-graph file.
+func (g *commitGraph) hasFile() bool {
+	return g.closer != nil
+}
+
+// isShallow reports whether the repository is a shallow clone.
+func (g *commitGraph) isShallow() bool {
+	return len(g.shallow) > 0
+}
+
+// Close releases the commit-graph file, if any.
+func (g *commitGraph) Close() error {
+	if g.closer == nil {
+		return nil
+	}
+	return g.closer.Close()
+}
+
+// load returns the node for h, reading it from the index if needed.
+// The node's generation may still be unknown.
+func (g *commitGraph) load(h plumbing.Hash) (*graphNode, error) {
+	if n, ok := g.nodes[h]; ok {
+		return n, nil
+	}
+	if err := g.ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	cn, err := g.index.Get(h)
+	if err != nil {
+		return nil, fmt.Errorf("load commit %s: %w", h, err)
+	}
+
+	n := &graphNode{
+		hash:    h,
+		parents: cn.ParentHashes(),
+	}
+
+	// History ends at the boundary of a shallow clone, as it does for
+	// git.
+	if g.shallow[h] {
+		n.parents = nil
+	}
+
+	// Commits outside the commit-graph file report an infinite
+	// generation; files written without generation data report zero.
+	if gen := cn.Generation(); gen != 0 && gen != math.MaxUint64 {
+		n.generation = gen
+	}
+
+	g.nodes[h] = n
+	return n, nil
+}
+
+// node returns the node for h with its generation resolved.
+func (g *commitGraph) node(h plumbing.Hash) (*graphNode, error) {
+	n, err := g.load(h)
+	if err != nil || n.generation != 0 {
+		return n, err
+	}
+
+	// Resolve generations in post-order with an explicit stack, so
+	// deep histories without a commit-graph file can't exhaust the
+	// goroutine stack.
+	stack := []*graphNode{n}
+	for len(stack) > 0 {
+		top := stack[len(stack)-1]
+		if top.generation != 0 {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		var gen uint64
+		pending := false
+		for _, p := range top.parents {
+			pn, err := g.load(p)
+			if err != nil {
+				return nil, err
+			}
+			if pn.generation == 0 {
+				stack = append(stack, pn)
+				pending = true
+				continue
+			}
+			gen = max(gen, pn.generation)
+		}
+
+		if !pending {
+			top.generation = gen + 1
+			stack = stack[:len(stack)-1]
+		}
+	}
+
+	return n, nil
+}
+
+// nodeQueue is a max-heap of nodes ordered by generation.  Popping in
+// that order visits every commit after all of its descendants that
+// are part of the same walk.
+type nodeQueue []*graphNode
+
+func (q nodeQueue) Len() int { return len(q) }
+func (q nodeQueue) Less(i, j int) bool {
+	if q[i].generation != q[j].generation {
+		return q[i].generation > q[j].generation
+	}
+	return bytes.Compare(q[i].hash[:], q[j].hash[:]) < 0
+}
+func (q nodeQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *nodeQueue) Push(x any)   { *q = append(*q, x.(*graphNode)) }
+func (q *nodeQueue) Pop() any {
+	old := *q
+	n := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return n
+}
+
+// ancestryWalker lazily enumerates every commit reachable from a set
+// of start commits.  The walk only advances as far as queries
+// require: asking whether a commit is reachable expands the frontier
+// down to that commit's generation and no further, so answering
+// questions about recent commits never touches old history.
+type ancestryWalker struct {
+	g     *commitGraph
+	queue nodeQueue
+	seen  map[plumbing.Hash]bool
+}
+
+func (g *commitGraph) newAncestryWalker(starts ...plumbing.Hash) (*ancestryWalker, error) {
+	w := &ancestryWalker{
+		g:    g,
+		seen: map[plumbing.Hash]bool{},
+	}
+
+	for _, h := range starts {
+		if w.seen[h] {
+			continue
+		}
+
+		n, err := g.node(h)
+		if err != nil {
+			return nil, err
+		}
+		w.seen[h] = true
+		heap.Push(&w.queue, n)
+	}
+
+	return w, nil
+}
+
+// IsAncestor reports whether commit a is reachable from commit b, or
+// is b.  Unlike object.Commit.IsAncestor it uses the commit-graph file
+// and stops at the boundary of a shallow clone, like BuildGuide, and
+// stops with ctx's error once ctx is done.
+func IsAncestor(ctx context.Context, cx *Context, a, b plumbing.Hash) (bool, error) {
+	g := newCommitGraph(ctx, cx)
+	defer func() { _ = g.Close() }()
+
+	w, err := g.newAncestryWalker(b)
+	if err != nil {
+		return false, err
+	}
+	return w.contains(a)
+}
+
+// step expands the frontier node with the highest generation.
+func (w *ancestryWalker) step() error {
+	n := heap.Pop(&w.queue).(*graphNode)
+	for _, p := range n.parents {
+		if w.seen[p] {
+			continue
+		}
+
+		pn, err := w.g.node(p)
+		if err != nil {
+			return err
+		}
+		w.seen[p] = true
+		heap.Push(&w.queue, pn)
+	}
+	return nil
+}
+
+// contains reports whether h is reachable from the start commits.
+func (w *ancestryWalker) contains(h plumbing.Hash) (bool, error) {
+	n, err := w.g.node(h)
+	if err != nil {
+		return false, err
+	}
+
+	// Every commit reachable at n's generation or above has been seen
+	// once the frontier drops below it.
+	for !w.seen[h] && w.queue.Len() > 0 && w.queue[0].generation >= n.generation {
+		if err := w.step(); err != nil {
+			return false, err
+		}
+	}
+
+	return w.seen[h], nil
+}
+
+// count walks the remaining history and returns the number of
+// commits reachable from the start commits.
+func (w *ancestryWalker) count() (int, error) {
+	for w.queue.Len() > 0 {
+		if err := w.step(); err != nil {
+			return 0, err
+		}
+	}
+	return len(w.seen), nil
+}
+
+// Paint flags used by countAhead.
+const (
+	paintInteresting = iota + 1
+	paintUninteresting
+)
+
+// countAhead counts commits reachable from head but not from base,
+// the same as `git rev-list --count base..head`.  Both sides are
+// walked together in generation order and the walk stops as soon as
+// only commits reachable from base remain, so its cost is bounded by
+// the size of the divergence rather than the size of the history.
+func (g *commitGraph) countAhead(head, base plumbing.Hash) (int, error) {
+	if head == base {
+		return 0, nil
+	}
+
+	var queue nodeQueue
+	paint := map[plumbing.Hash]int{}
+	interesting := 0
+
+	push := func(h plumbing.Hash, flag int) error {
+		switch paint[h] {
+		case paintUninteresting:
+			return nil
+		case paintInteresting:
+			if flag == paintUninteresting {
+				paint[h] = flag
+				interesting--
+			}
+			return nil
+		}
+
+		n, err := g.node(h)
+		if err != nil {
+			return err
+		}
+		paint[h] = flag
+		if flag == paintInteresting {
+			interesting++
+		}
+		heap.Push(&queue, n)
+		return nil
+	}
+
+	if err := push(base, paintUninteresting); err != nil {
+		return 0, err
+	}
+	if err := push(head, paintInteresting); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for interesting > 0 {
+		n := heap.Pop(&queue).(*graphNode)
+
+		// All descendants of n within the walk have been popped
+		// already, so its paint is final.
+		flag := paint[n.hash]
+		if flag == paintInteresting {
+			interesting--
+			count++
+		}
+
+		for _, p := range n.parents {
+			if err := push(p, flag); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return count, nil
+}
+
+// walkBranchTips prepares the walk over the commits reachable from
+// any local branch or remote-tracking ref, and reports whether there
+// is any such ref.  Remote-tracking refs are included because typical
+// CI checkouts and fresh clones leave every non-checked-out branch as
+// `refs/remotes/origin/*`; ignoring them would wrongly classify
+// legitimate release-branch tags as stranded.
+func (g *commitGraph) walkBranchTips(repo *git.Repository) (*ancestryWalker, bool, error) {
+	refIter, err := repo.References()
+	if err != nil {
+		return nil, false, fmt.Errorf("list references: %w", err)
+	}
+
+	var tips []plumbing.Hash
+	err = refIter.ForEach(func(ref *plumbing.Reference) error {
+		name := ref.Name()
+		if ref.Type() != plumbing.HashReference || (!name.IsBranch() && !name.IsRemote()) {
+			return nil
+		}
+		// Broken refs don't contribute to reachability.
+		switch _, err := g.node(ref.Hash()); {
+		case errors.Is(err, plumbing.ErrObjectNotFound):
+			return nil
+		case err != nil:
+			return fmt.Errorf("resolve %s: %w", name, err)
+		}
+		tips = append(tips, ref.Hash())
+		return nil
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("walk references: %w", err)
+	}
+
+	w, err := g.newAncestryWalker(tips...)
+	if err != nil {
+		return nil, false, err
+	}
+	return w, len(tips) > 0, nil
+}
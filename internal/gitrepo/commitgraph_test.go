@@ -0,0 +1,180 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package gitrepo_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	commitgraphfmt "github.com/go-git/go-git/v5/plumbing/format/commitgraph/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0x5a17ed/semverkzeug/internal/gitfixture"
+	"github.com/0x5a17ed/semverkzeug/internal/gitrepo"
+)
+
+// removeRefCache drops cached guides so the next BuildGuide call
+// walks the history again.
+func removeRefCache(tb testing.TB, cx *gitrepo.Context) {
+	tb.Helper()
+
+	err := cx.DotGitFilesystem().Remove(path.Join("semverkzeug", "cache.json"))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		require.NoError(tb, err)
+	}
+}
+
+// guideSummary reduces a freshly computed guide to comparable values.
+func guideSummary(tb testing.TB, cx *gitrepo.Context, rev string) string {
+	tb.Helper()
+
+	removeRefCache(tb, cx)
+
+	ref, err := gitrepo.ResolveRef(cx, rev)
+	require.NoError(tb, err)
+
+	guide, err := gitrepo.BuildGuide(tb.Context(), cx, ref, gitrepo.RootScope())
+	require.NoError(tb, err)
+
+	var tags []string
+	for _, vt := range guide.Tags {
+		tags = append(tags, vt.TagName)
+	}
+
+	mergeBase := plumbing.ZeroHash
+	if guide.MergeBase != nil {
+		mergeBase = guide.MergeBase.Hash
+	}
+
+	return fmt.Sprintf("tags=%v merge-base=%s depth=%d", tags, mergeBase, guide.Depth)
+}
+
+func removeCommitGraph(t *testing.T, cx *gitrepo.Context) {
+	t.Helper()
+
+	require.NoError(t, cx.DotGitFilesystem().Remove(path.Join("objects", "info", "commit-graph")))
+}
+
+// TestBuildGuide_CommitGraphMatchesObjectWalk verifies that guides
+// computed from a commit-graph file are identical to those computed
+// by walking commit objects, including for commits created after the
+// file was written.
+func TestBuildGuide_CommitGraphMatchesObjectWalk(t *testing.T) {
+	// Arrange: 60 commits with merges, tagged every 25 commits.
+	cx := gitfixture.RepoWithDeepHistory(t, 60, 25)
+
+	revs := []string{"HEAD", "HEAD~1", "HEAD~10", "HEAD~4^2", "v0.1.0", "v0.2.0", "v0.2.0~3", "HEAD~59"}
+
+	want := map[string]string{}
+	for _, rev := range revs {
+		want[rev] = guideSummary(t, cx, rev)
+	}
+
+	// Act: Describe the same revisions with a commit-graph present.
+	gitfixture.WriteCommitGraph(t, cx)
+
+	fsys := cx.DotGitFilesystem()
+	idx, err := commitgraphfmt.OpenChainOrFileIndex(fsys)
+	require.NoError(t, err)
+	require.NoError(t, idx.Close())
+
+	// Assert
+	for _, rev := range revs {
+		assert.Equal(t, want[rev], guideSummary(t, cx, rev), rev)
+	}
+
+	// Ten commits on main past v0.2.0 plus the side commit merged at
+	// commit 56.
+	tagRef, err := gitrepo.ResolveRef(cx, "v0.2.0")
+	require.NoError(t, err)
+	assert.Equal(t, "tags=[v0.2.0] merge-base="+tagRef.Hash().String()+" depth=11", want["HEAD"])
+
+	// Commits past the commit-graph file mix both sources.
+	t.Run("commits-outside-graph", func(t *testing.T) {
+		gitfixture.Checkout(t, cx, "main", false)
+		gitfixture.CommitFile(t, cx, "foo", "baa")
+		gitfixture.CommitFile(t, cx, "bar", "baa")
+
+		withGraph := guideSummary(t, cx, "HEAD")
+		removeCommitGraph(t, cx)
+
+		assert.Equal(t, guideSummary(t, cx, "HEAD"), withGraph)
+	})
+}
+
+func BenchmarkBuildGuide(b *testing.B) {
+	for _, depth := range []int{1000, 10000} {
+		for _, withGraph := range []bool{false, true} {
+			b.Run(fmt.Sprintf("depth=%d/commit-graph=%t", depth, withGraph), func(b *testing.B) {
+				cx := gitfixture.RepoWithDeepHistory(b, depth, 100)
+				if withGraph {
+					gitfixture.WriteCommitGraph(b, cx)
+				}
+
+				ref := gitfixture.Head(b, cx)
+
+				for b.Loop() {
+					removeRefCache(b, cx)
+
+					_, err := gitrepo.BuildGuide(b.Context(), cx, ref, gitrepo.RootScope())
+					if err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestIsAncestor(t *testing.T) {
+	cx := gitfixture.RepoWithTwoCommitsOneTagClean(t)
+	head := gitfixture.Head(t, cx).Hash()
+	commit, err := cx.Repository().CommitObject(head)
+	require.NoError(t, err)
+	parent := commit.ParentHashes[0]
+
+	tests := []struct {
+		name string
+		a, b plumbing.Hash
+		want bool
+	}{
+		{name: "parent", a: parent, b: head, want: true},
+		{name: "child", a: head, b: parent, want: false},
+		{name: "same", a: head, b: head, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := gitrepo.IsAncestor(t.Context(), cx, tt.a, tt.b)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+
+		_, err := gitrepo.IsAncestor(ctx, cx, parent, head)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
@@ -17,6 +17,7 @@
 package gitrepo
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strings"
@@ -43,7 +44,7 @@ func commonPrefixLength(a, b string) (i int) {
 	return i
 }
 
-func abbreviateCommitWithHashPrefix(r *git.Repository, h plumbing.Hash, st hashPrefixStorer) (string, error) {
+func abbreviateCommitWithHashPrefix(ctx context.Context, r *git.Repository, h plumbing.Hash, st hashPrefixStorer) (string, error) {
 	// HashesWithPrefix only accepts byte-aligned prefixes, but we want
 	// nibble-aligned answers down to minAbbrevNibbles. A single query at the
 	// floor byte boundary returns a superset of every commit that could
@@ -60,6 +61,9 @@ func abbreviateCommitWithHashPrefix(r *git.Repository, h plumbing.Hash, st hashP
 		if candidate == h {
 			continue
 		}
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
 		switch _, err := r.CommitObject(candidate); {
 		case errors.Is(err, plumbing.ErrObjectNotFound):
 			continue
@@ -79,7 +83,7 @@ func abbreviateCommitWithHashPrefix(r *git.Repository, h plumbing.Hash, st hashP
 	return full[:k], nil
 }
 
-func abbreviateCommitByScanning(r *git.Repository, h plumbing.Hash) (string, error) {
+func abbreviateCommitByScanning(ctx context.Context, r *git.Repository, h plumbing.Hash) (string, error) {
 	full := h.String()
 
 	for i, n := minAbbrevNibbles, len(full); i <= n; i++ {
@@ -94,6 +98,9 @@ func abbreviateCommitByScanning(r *git.Repository, h plumbing.Hash) (string, err
 		matchedTarget := false
 
 		err = iter.ForEach(func(c *object.Commit) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
 			if !strings.HasPrefix(c.Hash.String(), prefix) {
 				return nil
 			}
@@ -124,7 +131,7 @@ func abbreviateCommitByScanning(r *git.Repository, h plumbing.Hash) (string, err
 }
 
 // FindUniqueCommitHashAbbreviation returns a shortened hash of the commit that uniquely identifies the commit.
-func FindUniqueCommitHashAbbreviation(cx *Context, co *object.Commit) (string, error) {
+func FindUniqueCommitHashAbbreviation(ctx context.Context, cx *Context, co *object.Commit) (string, error) {
 	if co == nil || co.Hash == plumbing.ZeroHash {
 		return "", fmt.Errorf("commit is nil or has zero hash")
 	}
@@ -132,8 +139,23 @@ func FindUniqueCommitHashAbbreviation(cx *Context, co *object.Commit) (string, e
 	r := cx.Repository()
 
 	if store, ok := r.Storer.(hashPrefixStorer); ok {
-		return abbreviateCommitWithHashPrefix(r, co.Hash, store)
+		return abbreviateCommitWithHashPrefix(ctx, r, co.Hash, store)
 	}
 
-	return abbreviateCommitByScanning(r, co.Hash)
+	return abbreviateCommitByScanning(ctx, r, co.Hash)
+}
+
+// DescribeCommit returns the abbreviated hash of the commit followed by
+// its subject in parentheses, like "1a2b3c4 (fix parser)", for showing
+// commits to users.
+func DescribeCommit(ctx context.Context, cx *Context, co *object.Commit) (string, error) {
+	out, err := FindUniqueCommitHashAbbreviation(ctx, cx, co)
+	if err != nil {
+		return "", fmt.Errorf("abbreviate commit hash: %w", err)
+	}
+	subject, _, _ := strings.Cut(co.Message, "\n")
+	if subject = strings.TrimSpace(subject); subject != "" {
+		out = fmt.Sprintf("%s (%s)", out, subject)
+	}
+	return out, nil
 }
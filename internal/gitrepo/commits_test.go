@@ -17,6 +17,7 @@
 package gitrepo_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -31,8 +32,20 @@ func TestAbbreviatedCommitHash(t *testing.T) {
 
 	c := gitfixture.CommitFile(t, repo, "test.txt", "test")
 
-	got, err := gitrepo.FindUniqueCommitHashAbbreviation(repo, c)
+	got, err := gitrepo.FindUniqueCommitHashAbbreviation(t.Context(), repo, c)
 	require.NoError(t, err)
 
 	assert.Equal(t, c.Hash.String()[:7], got)
 }
+
+func TestDescribeCommit(t *testing.T) {
+	repo := gitfixture.RepoEmpty(t)
+
+	c := gitfixture.CommitFile(t, repo, "test.txt", "test")
+
+	got, err := gitrepo.DescribeCommit(t.Context(), repo, c)
+	require.NoError(t, err)
+
+	subject, _, _ := strings.Cut(c.Message, "\n")
+	assert.Equal(t, c.Hash.String()[:7]+" ("+strings.TrimSpace(subject)+")", got)
+}
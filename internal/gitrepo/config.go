@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	iofs "io/fs"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -41,6 +42,24 @@ type effectiveConfig struct {
 	excludesFile   *string
 	ignoreCase     bool
 	worktreeConfig bool
+
+	// fsmonitor is core.fsmonitor: a hook path, or a boolean
+	// selecting git's builtin daemon.
+	fsmonitor string
+	// untrackedCache is core.untrackedCache; nil when unset or
+	// "keep".
+	untrackedCache *bool
+
+	sparseCheckout     bool
+	sparseCheckoutCone bool
+
+	// ignoreSubmodules is diff.ignoreSubmodules, see
+	// SubmodulePolicy.
+	ignoreSubmodules string
+
+	// promisorRemote names the remote missing objects of a partial
+	// clone are fetched from; empty for complete repositories.
+	promisorRemote string
 }
 
 type configLoader struct {
@@ -187,6 +206,7 @@ func (l *configLoader) applyConfigFile(cf configFile, depth int) error {
 	case err != nil:
 		return fmt.Errorf("read git config %s: %w", cf.path, err)
 	}
+	slog.Debug("load git config", "file", key, "depth", depth)
 
 	walker := func(section, subsection, key, value string, boolValue bool) error {
 		return l.configWalker(cf, depth, section, subsection, key, value, boolValue)
@@ -209,16 +229,58 @@ func (l *configLoader) applyRawConfig(raw *formatconfig.Config) {
 		}
 	}
 
+	if core.HasOption("fsmonitor") {
+		l.cfg.fsmonitor = core.Option("fsmonitor")
+	}
+	if core.HasOption("untrackedcache") {
+		l.cfg.untrackedCache = parseUntrackedCacheOption(core.Option("untrackedcache"), false)
+	}
+
+	if core.HasOption("sparsecheckout") {
+		if b, err := parseGitBool(core.Option("sparsecheckout"), false); err == nil {
+			l.cfg.sparseCheckout = b
+		}
+	}
+	if core.HasOption("sparsecheckoutcone") {
+		if b, err := parseGitBool(core.Option("sparsecheckoutcone"), false); err == nil {
+			l.cfg.sparseCheckoutCone = b
+		}
+	}
+
+	if diff := raw.Section("diff"); diff.HasOption("ignoresubmodules") {
+		l.cfg.ignoreSubmodules = diff.Option("ignoresubmodules")
+	}
+
 	extensions := raw.Section("extensions")
 	if extensions.HasOption("worktreeconfig") {
 		if b, err := parseGitBool(extensions.Option("worktreeconfig"), false); err == nil {
 			l.cfg.worktreeConfig = b
 		}
 	}
+	if extensions.HasOption("partialclone") {
+		l.cfg.promisorRemote = extensions.Option("partialclone")
+	}
+
+	for _, remote := range raw.Section("remote").Subsections {
+		if remote.HasOption("promisor") {
+			if b, err := parseGitBool(remote.Option("promisor"), false); err == nil && b && l.cfg.promisorRemote == "" {
+				l.cfg.promisorRemote = remote.Name
+			}
+		}
+	}
 }
 
 func (l *configLoader) applyConfigOption(section, subsection, key, value string, boolValue bool) error {
 	if subsection != "" {
+		if strings.EqualFold(section, "remote") && strings.EqualFold(key, "promisor") {
+			b, err := parseGitBool(value, boolValue)
+			if err != nil {
+				return fmt.Errorf("parse remote.%s.promisor: %w", subsection, err)
+			}
+			if b && l.cfg.promisorRemote == "" {
+				l.cfg.promisorRemote = subsection
+			}
+		}
 		return nil
 	}
 
@@ -231,6 +293,26 @@ func (l *configLoader) applyConfigOption(section, subsection, key, value string,
 			return fmt.Errorf("parse core.ignoreCase: %w", err)
 		}
 		l.cfg.ignoreCase = b
+	case strings.EqualFold(section, "core") && strings.EqualFold(key, "fsmonitor"):
+		l.cfg.fsmonitor = value
+	case strings.EqualFold(section, "core") && strings.EqualFold(key, "untrackedcache"):
+		l.cfg.untrackedCache = parseUntrackedCacheOption(value, boolValue)
+	case strings.EqualFold(section, "core") && strings.EqualFold(key, "sparsecheckout"):
+		b, err := parseGitBool(value, boolValue)
+		if err != nil {
+			return fmt.Errorf("parse core.sparseCheckout: %w", err)
+		}
+		l.cfg.sparseCheckout = b
+	case strings.EqualFold(section, "core") && strings.EqualFold(key, "sparsecheckoutcone"):
+		b, err := parseGitBool(value, boolValue)
+		if err != nil {
+			return fmt.Errorf("parse core.sparseCheckoutCone: %w", err)
+		}
+		l.cfg.sparseCheckoutCone = b
+	case strings.EqualFold(section, "diff") && strings.EqualFold(key, "ignoresubmodules"):
+		l.cfg.ignoreSubmodules = value
+	case strings.EqualFold(section, "extensions") && strings.EqualFold(key, "partialclone"):
+		l.cfg.promisorRemote = value
 	case strings.EqualFold(section, "extensions") && strings.EqualFold(key, "worktreeconfig"):
 		b, err := parseGitBool(value, boolValue)
 		if err != nil {
@@ -387,6 +469,16 @@ func parseGitBool(value string, valueOmitted bool) (bool, error) {
 	}
 }
 
+// parseUntrackedCacheOption parses core.untrackedCache, which is a
+// boolean or "keep".  Returns nil for "keep" and invalid values.
+func parseUntrackedCacheOption(value string, valueOmitted bool) *bool {
+	b, err := parseGitBool(value, valueOmitted)
+	if err != nil {
+		return nil
+	}
+	return &b
+}
+
 func expandTildeInGitdirPattern(p string) (string, error) {
 	hadTrailingSlash := strings.HasSuffix(p, "/")
 
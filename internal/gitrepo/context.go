@@ -23,14 +23,23 @@ import (
 	"sync"
 
 	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-billy/v5/osfs"
 	"github.com/go-git/go-git/v5"
 	storagevfs "github.com/go-git/go-git/v5/storage/filesystem"
+	"github.com/go-git/go-git/v5/storage/filesystem/dotgit"
 	storagemem "github.com/go-git/go-git/v5/storage/memory"
 )
 
 type Context struct {
 	repo *git.Repository
 
+	submodules SubmodulePolicy
+	coerceTags bool
+	scheme     Scheme
+
+	maintenanceBranches []string
+	branchLabels        *BranchLabels
+
 	wt struct {
 		once  sync.Once
 		value *git.Worktree
@@ -77,7 +86,19 @@ func (cx *Context) DotGitFilesystem() billy.Filesystem {
 	return st.Filesystem()
 }
 
-// DotGitPath returns the path to the .git directory.
+// worktreeDotGitFilesystem returns the git directory of the worktree
+// for the files git keeps per worktree although go-git looks for them
+// in the common directory, such as info/sparse-checkout.
+func (cx *Context) worktreeDotGitFilesystem() billy.Filesystem {
+	fsys := cx.DotGitFilesystem()
+	if _, ok := fsys.(*dotgit.RepositoryFilesystem); ok {
+		return osfs.New(fsys.Root())
+	}
+	return fsys
+}
+
+// DotGitPath returns the path to the git directory of the worktree:
+// the .git directory, or .git/worktrees/<name> for a linked worktree.
 func (cx *Context) DotGitPath() (string, bool) {
 	fsys := cx.DotGitFilesystem()
 	if fsys == nil {
@@ -86,6 +107,50 @@ func (cx *Context) DotGitPath() (string, bool) {
 	return fsys.Root(), true
 }
 
+// CommonDotGitPath returns the path to the git directory shared by all
+// worktrees, holding the refs and objects.  It's the same as
+// DotGitPath except in linked worktrees.
+func (cx *Context) CommonDotGitPath() (string, bool) {
+	fsys := cx.DotGitFilesystem()
+	if fsys == nil {
+		return "", false
+	}
+
+	common, err := readCommonDir(osfs.New(fsys.Root()))
+	if err != nil || common == "" {
+		return fsys.Root(), true
+	}
+	return common, true
+}
+
+// SetSubmodulePolicy sets how submodules affect the worktree status,
+// overriding diff.ignoreSubmodules.
+func (cx *Context) SetSubmodulePolicy(p SubmodulePolicy) {
+	cx.submodules = p
+}
+
+// SetCoerceTags sets whether tags that are almost semantic versions,
+// like "v1.2" or "1.2.3.4", are coerced into versions rather than
+// ignored.  See ParseVersionSpecTolerant.
+func (cx *Context) SetCoerceTags(coerce bool) {
+	cx.coerceTags = coerce
+}
+
+// SetScheme sets the versioning scheme of version tags.  Tags of other
+// schemes are ignored.
+func (cx *Context) SetScheme(s Scheme) {
+	cx.scheme = s
+}
+
+// Scheme returns the versioning scheme of version tags, SemVer unless
+// set otherwise.
+func (cx *Context) Scheme() Scheme {
+	if cx.scheme == nil {
+		return SemVer
+	}
+	return cx.scheme
+}
+
 // LoadWorktree returns a worktree for the repository.
 func (cx *Context) LoadWorktree() (*git.Worktree, error) {
 	cx.wt.once.Do(func() {
@@ -132,10 +197,7 @@ func NewContextFromRepo(r *git.Repository) (*Context, error) {
 // NewContextFromPath creates a new Context from a git repository at the given
 // path on the filesystem.
 func NewContextFromPath(p string) (*Context, error) {
-	repo, err := git.PlainOpenWithOptions(p, &git.PlainOpenOptions{
-		DetectDotGit:          true,
-		EnableDotGitCommonDir: true,
-	})
+	repo, err := openRepository(p)
 	if err != nil {
 		return nil, fmt.Errorf("open repository %#q: %w", p, err)
 	}
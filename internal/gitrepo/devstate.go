@@ -17,12 +17,14 @@
 package gitrepo
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io/fs"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"slices"
@@ -38,8 +40,10 @@ import (
 // centisecond resolution used by the floating dev version label.
 const devCounterTick = 10 * time.Millisecond
 
-// devStateRelPath is the per-worktree path (relative to the gitdir) where
-// the dev counter state is persisted.
+// devStateRelPath is the per-worktree path (relative to the gitdir of the
+// worktree, .git/worktrees/<name> for a linked one) where the dev counter
+// state is persisted.  Worktrees have their own dirty files, so sharing
+// the state would make them clobber each other's fingerprints.
 const devStateRelPath = "semverkzeug/state.json"
 
 // devState is the on-disk record consulted by FindStableWorktreeMTime.
@@ -154,13 +158,51 @@ func computeFingerprint(entries []DirtyEntry) string {
 // written (in-memory storage, read-only .git, corrupted state), the
 // function degrades to plain max(index mtime, dirty file mtimes)
 // without surfacing an error.
-func FindStableWorktreeMTime(cx *Context) (*time.Time, error) {
+//
+// Once ctx is done, the function returns its error and leaves the
+// state file untouched; the file is only ever replaced atomically.
+func FindStableWorktreeMTime(ctx context.Context, cx *Context) (*time.Time, error) {
+	r, err := ExplainStableWorktreeMTime(ctx, cx)
+	if err != nil {
+		return nil, err
+	}
+	return &r.Emitted, nil
+}
+
+// WorktreeMTime is how FindStableWorktreeMTime arrived at its result.
+type WorktreeMTime struct {
+	// Entries are the dirty entries, and IndexMTime the mtime of the
+	// index, nil without one.
+	Entries    []DirtyEntry
+	IndexMTime *time.Time
+
+	// Fingerprint identifies Entries.  Previous is the time emitted by
+	// the previous call, nil without its state, and Matched reports
+	// whether its fingerprint is the same, so Previous was emitted
+	// again.
+	Fingerprint string
+	Previous    *time.Time
+	Matched     bool
+
+	// Candidate is the latest of IndexMTime and the entry mtimes.
+	// Floored reports whether it didn't advance past the time
+	// previously emitted, which was advanced by a tick instead.
+	// Both are unset when Matched.
+	Candidate time.Time
+	Floored   bool
+
+	Emitted time.Time
+}
+
+// ExplainStableWorktreeMTime is FindStableWorktreeMTime that also
+// reports how it arrived at its result.
+func ExplainStableWorktreeMTime(ctx context.Context, cx *Context) (*WorktreeMTime, error) {
 	indexMTime, err := findIndexMTime(cx)
 	if err != nil {
 		return nil, fmt.Errorf("find index mtime: %w", err)
 	}
 
-	entriesIter, doneFn := IterDirtyEntries(cx)
+	entriesIter, doneFn := IterDirtyEntries(ctx, cx)
 	entries := slices.Collect(entriesIter)
 	if err := doneFn(); err != nil {
 		return nil, fmt.Errorf("find dirty entries: %w", err)
@@ -168,6 +210,9 @@ func FindStableWorktreeMTime(cx *Context) (*time.Time, error) {
 	if len(entries) == 0 {
 		return nil, ErrWorktreeClean
 	}
+	for i := range entries {
+		slog.DebugContext(ctx, "dirty entry", "path", entries[i].Path(), "mtime", entries[i].ModTime())
+	}
 
 	var prev *devState
 	statePath, hasStateStorage := devStatePath(cx)
@@ -179,40 +224,57 @@ func FindStableWorktreeMTime(cx *Context) (*time.Time, error) {
 		}
 	}
 
-	fingerprint := computeFingerprint(entries)
+	r := &WorktreeMTime{
+		Entries:     entries,
+		IndexMTime:  indexMTime,
+		Fingerprint: computeFingerprint(entries),
+	}
+	if prev != nil {
+		r.Previous = &prev.Emitted
+	}
 
 	// Stability fast path: identical inputs as last time, return the
 	// previously emitted value verbatim and skip writing the new state.
-	if prev != nil && prev.Fingerprint == fingerprint {
-		return new(prev.Emitted), nil
+	if prev != nil && prev.Fingerprint == r.Fingerprint {
+		r.Matched = true
+		r.Emitted = prev.Emitted
+		slog.DebugContext(ctx, "reuse dev state", "fingerprint", r.Fingerprint, "mtime", r.Emitted)
+		return r, nil
 	}
 
 	// Compute the unfloored candidate as max(index mtime, dirty file mtimes).
-	var candidate time.Time
 	if indexMTime != nil {
-		candidate = *indexMTime
+		r.Candidate = *indexMTime
 	}
 	for _, e := range entries {
-		if e.ModTime().After(candidate) {
-			candidate = e.ModTime()
+		if e.ModTime().After(r.Candidate) {
+			r.Candidate = e.ModTime()
 		}
 	}
 
 	// Apply the monotonicity floor.
-	emitted := candidate
-	if prev != nil && !candidate.After(prev.Emitted) {
-		emitted = prev.Emitted.Add(devCounterTick)
+	r.Emitted = r.Candidate
+	if prev != nil && !r.Candidate.After(prev.Emitted) {
+		r.Emitted = prev.Emitted.Add(devCounterTick)
+		r.Floored = true
+	}
+
+	// The emitted value is only meaningful when it gets returned.
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
 
+	slog.DebugContext(ctx, "update dev state", "fingerprint", r.Fingerprint, "mtime", r.Emitted, "floored", r.Floored)
+
 	if hasStateStorage {
 		err := saveDevState(statePath, devState{
-			Fingerprint: fingerprint,
-			Emitted:     emitted,
+			Fingerprint: r.Fingerprint,
+			Emitted:     r.Emitted,
 		})
 		if err != nil {
 			return nil, fmt.Errorf("save state: %w", err)
 		}
 	}
 
-	return &emitted, nil
+	return r, nil
 }
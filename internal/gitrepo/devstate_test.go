@@ -17,6 +17,8 @@
 package gitrepo_test
 
 import (
+	"context"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"testing"
@@ -41,7 +43,7 @@ func TestFindStableWorktreeMTime(t *testing.T) {
 	t.Run("clean repo returns ErrWorktreeClean", func(t *testing.T) {
 		cx := gitfixture.RepoWithOneCommitNoTagsClean(t)
 
-		mtime, err := gitrepo.FindStableWorktreeMTime(cx)
+		mtime, err := gitrepo.FindStableWorktreeMTime(t.Context(), cx)
 		require.ErrorIs(t, err, gitrepo.ErrWorktreeClean)
 		assert.Nil(t, mtime)
 	})
@@ -50,7 +52,7 @@ func TestFindStableWorktreeMTime(t *testing.T) {
 		cx := gitfixture.RepoWithOneCommitNoTagsClean(t)
 		gitfixture.WriteRepoFile(t, cx, "foo", "baz")
 
-		_, err := gitrepo.FindStableWorktreeMTime(cx)
+		_, err := gitrepo.FindStableWorktreeMTime(t.Context(), cx)
 		require.NoError(t, err)
 
 		info, err := os.Stat(statePath(t, cx))
@@ -62,7 +64,7 @@ func TestFindStableWorktreeMTime(t *testing.T) {
 		cx := gitfixture.RepoWithOneCommitNoTagsClean(t)
 		gitfixture.WriteRepoFile(t, cx, "foo", "baz")
 
-		first, err := gitrepo.FindStableWorktreeMTime(cx)
+		first, err := gitrepo.FindStableWorktreeMTime(t.Context(), cx)
 		require.NoError(t, err)
 		require.NotNil(t, first)
 
@@ -73,7 +75,7 @@ func TestFindStableWorktreeMTime(t *testing.T) {
 		future := first.Add(1 * time.Hour)
 		require.NoError(t, os.Chtimes(indexPath, future, future))
 
-		second, err := gitrepo.FindStableWorktreeMTime(cx)
+		second, err := gitrepo.FindStableWorktreeMTime(t.Context(), cx)
 		require.NoError(t, err)
 		require.NotNil(t, second)
 
@@ -85,7 +87,7 @@ func TestFindStableWorktreeMTime(t *testing.T) {
 		cx := gitfixture.RepoWithOneCommitNoTagsClean(t)
 		gitfixture.WriteRepoFile(t, cx, "foo", "baz")
 
-		first, err := gitrepo.FindStableWorktreeMTime(cx)
+		first, err := gitrepo.FindStableWorktreeMTime(t.Context(), cx)
 		require.NoError(t, err)
 		require.NotNil(t, first)
 
@@ -97,7 +99,7 @@ func TestFindStableWorktreeMTime(t *testing.T) {
 		require.NoError(t, os.Chtimes(filepath.Join(wtRoot, "foo"), past, past))
 		require.NoError(t, os.Chtimes(filepath.Join(wtRoot, ".git", "index"), past, past))
 
-		second, err := gitrepo.FindStableWorktreeMTime(cx)
+		second, err := gitrepo.FindStableWorktreeMTime(t.Context(), cx)
 		require.NoError(t, err)
 		require.NotNil(t, second)
 
@@ -111,7 +113,7 @@ func TestFindStableWorktreeMTime(t *testing.T) {
 		cx := gitfixture.RepoWithOneCommitNoTagsClean(t)
 		gitfixture.WriteRepoFile(t, cx, "foo", "baz")
 
-		first, err := gitrepo.FindStableWorktreeMTime(cx)
+		first, err := gitrepo.FindStableWorktreeMTime(t.Context(), cx)
 		require.NoError(t, err)
 		require.NotNil(t, first)
 
@@ -120,7 +122,7 @@ func TestFindStableWorktreeMTime(t *testing.T) {
 		future := first.Add(2 * time.Hour)
 		require.NoError(t, os.Chtimes(filepath.Join(wtRoot, "foo"), future, future))
 
-		second, err := gitrepo.FindStableWorktreeMTime(cx)
+		second, err := gitrepo.FindStableWorktreeMTime(t.Context(), cx)
 		require.NoError(t, err)
 		require.NotNil(t, second)
 
@@ -132,15 +134,48 @@ func TestFindStableWorktreeMTime(t *testing.T) {
 		cx := gitfixture.RepoWithOneCommitNoTagsClean(t)
 		gitfixture.WriteRepoFile(t, cx, "foo", "baz")
 
-		first, err := gitrepo.FindStableWorktreeMTime(cx)
+		first, err := gitrepo.FindStableWorktreeMTime(t.Context(), cx)
 		require.NoError(t, err)
 
 		for range 5 {
-			next, err := gitrepo.FindStableWorktreeMTime(cx)
+			next, err := gitrepo.FindStableWorktreeMTime(t.Context(), cx)
 			require.NoError(t, err)
 			require.NotNil(t, next)
 			assert.True(t, next.Equal(*first),
 				"expected idempotence: first=%s next=%s", first, next)
 		}
 	})
+	t.Run("explain reports the fingerprint match", func(t *testing.T) {
+		cx := gitfixture.RepoWithOneCommitNoTagsClean(t)
+		gitfixture.WriteRepoFile(t, cx, "foo", "baz")
+
+		first, err := gitrepo.ExplainStableWorktreeMTime(t.Context(), cx)
+		require.NoError(t, err)
+		require.Len(t, first.Entries, 1)
+		assert.Equal(t, "foo", first.Entries[0].Path())
+		assert.Nil(t, first.Previous)
+		assert.False(t, first.Matched)
+
+		second, err := gitrepo.ExplainStableWorktreeMTime(t.Context(), cx)
+		require.NoError(t, err)
+		require.NotNil(t, second.Previous)
+		assert.True(t, second.Matched)
+		assert.Equal(t, first.Fingerprint, second.Fingerprint)
+		assert.True(t, second.Emitted.Equal(first.Emitted))
+	})
+
+	t.Run("canceled call leaves state untouched", func(t *testing.T) {
+		cx := gitfixture.RepoWithOneCommitNoTagsClean(t)
+		gitfixture.WriteRepoFile(t, cx, "foo", "baz")
+
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+
+		mtime, err := gitrepo.FindStableWorktreeMTime(ctx, cx)
+		require.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, mtime)
+
+		_, err = os.Stat(statePath(t, cx))
+		assert.ErrorIs(t, err, fs.ErrNotExist)
+	})
 }
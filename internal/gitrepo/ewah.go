@@ -0,0 +1,111 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package gitrepo
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/bits"
+)
+
+var errMalformedEWAH = errors.New("malformed ewah bitmap")
+
+// ewahBitmap is a decoded EWAH compressed bitmap as used by git's
+// index extensions.
+type ewahBitmap struct {
+	size  int
+	words []uint64
+}
+
+// has reports whether bit i is set.  Bits beyond the bitmap's size
+// are unset.
+func (b *ewahBitmap) has(i int) bool {
+	if i < 0 || i >= b.size || i/64 >= len(b.words) {
+		return false
+	}
+	return b.words[i/64]&(1<<(i%64)) != 0
+}
+
+// ones returns the positions of the set bits in ascending order.
+func (b *ewahBitmap) ones() []int {
+	var out []int
+	for wi, w := range b.words {
+		for w != 0 {
+			i := wi*64 + bits.TrailingZeros64(w)
+			if i >= b.size {
+				return out
+			}
+			out = append(out, i)
+			w &= w - 1
+		}
+	}
+	return out
+}
+
+// decodeEWAH decodes the on-disk bitmap at the start of data (see
+// git's ewah/ewah_io.c) and returns the remaining bytes.
+//
+// The layout is the bit count, the number of 64-bit words, the words
+// themselves and the position of the last run-length word, all
+// big-endian.  Each run-length word holds a running bit (bit 0), a
+// 32-bit run length of words filled with that bit and a 31-bit count
+// of literal words following it.
+func decodeEWAH(data []byte) (*ewahBitmap, []byte, error) {
+	if len(data) < 8 {
+		return nil, nil, errMalformedEWAH
+	}
+	bitSize := int(binary.BigEndian.Uint32(data))
+	wordCount := int(binary.BigEndian.Uint32(data[4:]))
+	data = data[8:]
+
+	if wordCount > len(data)/8 || len(data)-wordCount*8 < 4 {
+		return nil, nil, errMalformedEWAH
+	}
+	compressed := make([]uint64, wordCount)
+	for i := range compressed {
+		compressed[i] = binary.BigEndian.Uint64(data[i*8:])
+	}
+	data = data[wordCount*8+4:]
+
+	b := &ewahBitmap{size: bitSize}
+	maxWords := (bitSize + 63) / 64
+	for i := 0; i < len(compressed); {
+		rlw := compressed[i]
+		i++
+
+		running := rlw&1 != 0
+		runLength := int((rlw >> 1) & 0xffffffff)
+		literals := int(rlw >> 33)
+
+		if len(b.words)+runLength+literals > maxWords || i+literals > len(compressed) {
+			return nil, nil, errMalformedEWAH
+		}
+
+		var fill uint64
+		if running {
+			fill = ^uint64(0)
+		}
+		for range runLength {
+			b.words = append(b.words, fill)
+		}
+
+		b.words = append(b.words, compressed[i:i+literals]...)
+		i += literals
+	}
+
+	return b, data, nil
+}
@@ -0,0 +1,49 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package gitrepo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+)
+
+// BuildWorktreeStatusWithoutStatCache computes the status by comparing
+// the content of every file, for checking the stat cache fast path
+// against.
+func BuildWorktreeStatusWithoutStatCache(ctx context.Context, cx *Context) (git.Status, error) {
+	st, _, err := buildWorktreeStatus(ctx, cx, false)
+	return st, err
+}
+
+// SetFSMonitorTimeout changes how long the fsmonitor hook may take for
+// the duration of the test.
+func SetFSMonitorTimeout(t *testing.T, d time.Duration) {
+	prev := fsmonitorTimeout
+	fsmonitorTimeout = d
+	t.Cleanup(func() { fsmonitorTimeout = prev })
+}
+
+// SetPromisorRetryInterval changes how long unresolvable promised tags
+// are skipped for the duration of the test.
+func SetPromisorRetryInterval(t *testing.T, d time.Duration) {
+	prev := promisorRetryInterval
+	promisorRetryInterval = d
+	t.Cleanup(func() { promisorRetryInterval = prev })
+}
@@ -0,0 +1,137 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package gitrepo
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// fsmonitorTimeout bounds how long the fsmonitor hook may take to
+// answer before the status falls back to a full scan.
+var fsmonitorTimeout = 5 * time.Second
+
+// fsmonitorChanges is what an fsmonitor hook reported as changed
+// since the token recorded in the index.
+type fsmonitorChanges struct {
+	// all is set when the hook could not narrow down the changes.
+	all bool
+
+	// paths holds the reported paths.  Reported directories stand
+	// for everything below them.
+	paths map[string]bool
+
+	// dirs holds the parent directories of the reported paths, whose
+	// listing may have changed.
+	dirs map[string]bool
+}
+
+// touches reports whether p or one of its ancestors was reported.
+func (c *fsmonitorChanges) touches(p string) bool {
+	if c.all {
+		return true
+	}
+	for ; p != "." && p != ""; p = path.Dir(p) {
+		if c.paths[p] {
+			return true
+		}
+	}
+	return false
+}
+
+// dirChanged reports whether entries may have been added to or
+// removed from directory dir.
+func (c *fsmonitorChanges) dirChanged(dir string) bool {
+	if dir == "" {
+		dir = "."
+	}
+	return c.all || c.dirs[dir] || c.touches(dir)
+}
+
+// isFSMonitorHook reports whether a core.fsmonitor value names a hook
+// rather than enabling or disabling git's builtin daemon, which is not
+// supported.
+func isFSMonitorHook(value string) bool {
+	if value == "" {
+		return false
+	}
+	_, err := parseGitBool(value, false)
+	return err != nil
+}
+
+// queryFSMonitor asks the fsmonitor hook which paths changed since
+// the state recorded in the index, using the hook protocol version
+// matching the index extension (see githooks(5), fsmonitor-watchman).
+// The hook runs in the worktree root and is killed after
+// fsmonitorTimeout.
+func queryFSMonitor(ctx context.Context, hook, worktreeRoot string, st *fsmonitorState) (*fsmonitorChanges, error) {
+	hook, err := expandTilde(hook)
+	if err != nil {
+		return nil, err
+	}
+	if !filepath.IsAbs(hook) && strings.ContainsRune(hook, filepath.Separator) {
+		hook = filepath.Join(worktreeRoot, hook)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, fsmonitorTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, hook, strconv.Itoa(st.version), st.token)
+	cmd.Dir = worktreeRoot
+	// Don't wait for children of the hook holding on to its output.
+	cmd.WaitDelay = time.Second
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("run fsmonitor hook: %w", err)
+	}
+
+	fields := strings.Split(string(bytes.TrimRight(out, "\x00")), "\x00")
+	if st.version == 2 {
+		// The first field is the hook's new token.
+		if len(fields) == 0 || fields[0] == "" {
+			return nil, fmt.Errorf("run fsmonitor hook: missing token")
+		}
+		fields = fields[1:]
+	}
+
+	c := &fsmonitorChanges{
+		paths: map[string]bool{},
+		dirs:  map[string]bool{},
+	}
+	for _, f := range fields {
+		if f == "" {
+			continue
+		}
+		if f == "/" {
+			return &fsmonitorChanges{all: true}, nil
+		}
+
+		p := strings.TrimSuffix(f, "/")
+		c.paths[p] = true
+		c.dirs[path.Dir(p)] = true
+	}
+
+	return c, nil
+}
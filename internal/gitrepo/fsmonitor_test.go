@@ -0,0 +1,310 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package gitrepo_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0x5a17ed/semverkzeug/internal/gitfixture"
+	"github.com/0x5a17ed/semverkzeug/internal/gitrepo"
+)
+
+// fakeFSMonitor is a stand-in for watchman: a hook script reporting
+// the paths written to its changes file and recording its arguments.
+type fakeFSMonitor struct {
+	hook, changes, args string
+}
+
+func newFakeFSMonitor(t *testing.T) *fakeFSMonitor {
+	t.Helper()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("fsmonitor hook fixture is a shell script")
+	}
+
+	dir := t.TempDir()
+	m := &fakeFSMonitor{
+		hook:    filepath.Join(dir, "query-fsmonitor"),
+		changes: filepath.Join(dir, "changes"),
+		args:    filepath.Join(dir, "args"),
+	}
+
+	script := "#!/bin/sh\n" +
+		"echo \"$@\" > '" + m.args + "'\n" +
+		"test -f '" + m.changes + "' || exit 1\n" +
+		"printf 'new-token\\000'\n" +
+		"cat '" + m.changes + "'\n"
+	require.NoError(t, os.WriteFile(m.hook, []byte(script), 0o755))
+
+	return m
+}
+
+// report makes the hook answer with paths as changed.
+func (m *fakeFSMonitor) report(t *testing.T, paths ...string) {
+	t.Helper()
+
+	var out []byte
+	for _, p := range paths {
+		out = append(out, p...)
+		out = append(out, 0)
+	}
+	require.NoError(t, os.WriteFile(m.changes, out, 0o644))
+}
+
+func writeRepoConfig(t *testing.T, cx *gitrepo.Context, content string) {
+	t.Helper()
+
+	dotGit, ok := cx.DotGitPath()
+	require.True(t, ok)
+	gitfixture.WriteFile(t, filepath.Join(dotGit, "config"), content)
+}
+
+func TestStatus_FSMonitor(t *testing.T) {
+	_ = gitfixture.NewHomeFixture(t)
+	gitfixture.IsolateGitConfig(t)
+
+	arrange := func(t *testing.T) (*gitrepo.Context, *fakeFSMonitor) {
+		m := newFakeFSMonitor(t)
+
+		cx := gitfixture.RepoWithOneCommitNoTagsClean(t)
+		gitfixture.CommitFile(t, cx, "dir/bar", "baa")
+		writeRepoConfig(t, cx, "[core]\n\tfsmonitor = "+m.hook+"\n")
+		gitfixture.AppendIndexExtension(t, cx, "FSMN", gitfixture.FSMonitorExtension("token-1", 2))
+
+		// Modify a file behind the monitor's back.
+		gitfixture.WriteRepoFile(t, cx, "foo", "changed content")
+
+		return cx, m
+	}
+
+	t.Run("unreported changes are trusted away", func(t *testing.T) {
+		cx, m := arrange(t)
+		m.report(t)
+
+		st, err := gitrepo.BuildWorktreeStatus(t.Context(), cx)
+		require.NoError(t, err)
+
+		assert.True(t, st.IsClean(), "status:\n%s", st)
+
+		args, err := os.ReadFile(m.args)
+		require.NoError(t, err)
+		assert.Equal(t, "2 token-1\n", string(args))
+	})
+
+	t.Run("reported paths are checked", func(t *testing.T) {
+		cx, m := arrange(t)
+		m.report(t, "foo")
+
+		st, err := gitrepo.BuildWorktreeStatus(t.Context(), cx)
+		require.NoError(t, err)
+
+		assert.Equal(t, git.Modified, requireStatus(t, st, "foo").Worktree)
+	})
+
+	t.Run("reported directories cover their content", func(t *testing.T) {
+		cx, m := arrange(t)
+		gitfixture.WriteRepoFile(t, cx, "dir/bar", "changed content")
+		m.report(t, "dir/")
+
+		st, err := gitrepo.BuildWorktreeStatus(t.Context(), cx)
+		require.NoError(t, err)
+
+		assert.Equal(t, git.Modified, requireStatus(t, st, "dir/bar").Worktree)
+		assert.NotContains(t, st, "foo")
+	})
+
+	t.Run("failing hook falls back to a full scan", func(t *testing.T) {
+		cx, _ := arrange(t)
+
+		st, err := gitrepo.BuildWorktreeStatus(t.Context(), cx)
+		require.NoError(t, err)
+
+		assert.Equal(t, git.Modified, requireStatus(t, st, "foo").Worktree)
+	})
+
+	t.Run("slow hook falls back to a full scan", func(t *testing.T) {
+		cx, m := arrange(t)
+		m.report(t)
+		gitfixture.WriteFile(t, m.hook, "#!/bin/sh\nexec sleep 10\n")
+		gitrepo.SetFSMonitorTimeout(t, 100*time.Millisecond)
+
+		start := time.Now()
+		st, err := gitrepo.BuildWorktreeStatus(t.Context(), cx)
+		require.NoError(t, err)
+
+		assert.Equal(t, git.Modified, requireStatus(t, st, "foo").Worktree)
+		assert.Less(t, time.Since(start), 5*time.Second)
+	})
+
+	t.Run("dirty entries in the extension are checked", func(t *testing.T) {
+		m := newFakeFSMonitor(t)
+		m.report(t)
+
+		cx := gitfixture.RepoWithOneCommitNoTagsClean(t)
+		writeRepoConfig(t, cx, "[core]\n\tfsmonitor = "+m.hook+"\n")
+		gitfixture.AppendIndexExtension(t, cx, "FSMN", gitfixture.FSMonitorExtension("token-1", 1, 0))
+		gitfixture.WriteRepoFile(t, cx, "foo", "changed content")
+
+		st, err := gitrepo.BuildWorktreeStatus(t.Context(), cx)
+		require.NoError(t, err)
+
+		assert.Equal(t, git.Modified, requireStatus(t, st, "foo").Worktree)
+	})
+}
+
+func TestStatus_UntrackedCache(t *testing.T) {
+	_ = gitfixture.NewHomeFixture(t)
+	gitfixture.IsolateGitConfig(t)
+
+	// arrange records "cached" as the only untracked file, then sneaks
+	// in another file without changing the directory's mtime.
+	arrange := func(t *testing.T) *gitrepo.Context {
+		cx := gitfixture.RepoWithOneCommitNoTagsClean(t)
+		gitfixture.CommitFile(t, cx, "dir/bar", "baa")
+		gitfixture.WriteRepoFile(t, cx, "cached", "baa")
+
+		gitfixture.AppendIndexExtension(t, cx, "UNTR", gitfixture.UntrackedCacheExtension(t, cx, gitfixture.UntrackedCacheDir{
+			Untracked: []string{"cached"},
+			Dirs:      []gitfixture.UntrackedCacheDir{{Name: "dir"}},
+		}))
+
+		root := gitfixture.Filesystem(t, cx).Root()
+		fi, err := os.Stat(root)
+		require.NoError(t, err)
+		gitfixture.WriteRepoFile(t, cx, "sneaky", "baa")
+		require.NoError(t, os.Chtimes(root, fi.ModTime(), fi.ModTime()))
+
+		return cx
+	}
+
+	t.Run("unchanged directories use the cached listing", func(t *testing.T) {
+		cx := arrange(t)
+
+		st, err := gitrepo.BuildWorktreeStatus(t.Context(), cx)
+		require.NoError(t, err)
+
+		assert.Equal(t, git.Untracked, requireStatus(t, st, "cached").Worktree)
+		assert.NotContains(t, st, "sneaky")
+	})
+
+	t.Run("changed directories are read again", func(t *testing.T) {
+		cx := arrange(t)
+		future := time.Now().Add(time.Hour)
+		require.NoError(t, os.Chtimes(gitfixture.Filesystem(t, cx).Root(), future, future))
+
+		st, err := gitrepo.BuildWorktreeStatus(t.Context(), cx)
+		require.NoError(t, err)
+
+		assert.Equal(t, git.Untracked, requireStatus(t, st, "cached").Worktree)
+		assert.Equal(t, git.Untracked, requireStatus(t, st, "sneaky").Worktree)
+	})
+
+	t.Run("changed gitignore invalidates the listing", func(t *testing.T) {
+		cx := arrange(t)
+		root := gitfixture.Filesystem(t, cx).Root()
+		fi, err := os.Stat(root)
+		require.NoError(t, err)
+		gitfixture.WriteRepoFile(t, cx, ".gitignore", "cached\n.gitignore\n")
+		require.NoError(t, os.Chtimes(root, fi.ModTime(), fi.ModTime()))
+
+		st, err := gitrepo.BuildWorktreeStatus(t.Context(), cx)
+		require.NoError(t, err)
+
+		assert.NotContains(t, st, "cached")
+		assert.Equal(t, git.Untracked, requireStatus(t, st, "sneaky").Worktree)
+	})
+
+	t.Run("disabled by core.untrackedCache", func(t *testing.T) {
+		cx := arrange(t)
+		writeRepoConfig(t, cx, "[core]\n\tuntrackedCache = false\n")
+
+		st, err := gitrepo.BuildWorktreeStatus(t.Context(), cx)
+		require.NoError(t, err)
+
+		assert.Equal(t, git.Untracked, requireStatus(t, st, "sneaky").Worktree)
+	})
+}
+
+// TestStatus_IndexExtensionsWrittenByGit checks that the FSMN and UNTR
+// extensions are read as native git writes them.
+func TestStatus_IndexExtensionsWrittenByGit(t *testing.T) {
+	_ = gitfixture.NewHomeFixture(t)
+	gitfixture.IsolateGitConfig(t)
+
+	// newRepo creates a repository with a clean commit of foo and
+	// dir/bar.
+	newRepo := func(t *testing.T) string {
+		dir := t.TempDir()
+		gitfixture.RunGit(t, dir, "init", "-q", "-b", "main")
+		gitfixture.WriteFile(t, filepath.Join(dir, "foo"), "foo")
+		gitfixture.WriteFile(t, filepath.Join(dir, "dir", "bar"), "bar")
+		gitfixture.RunGit(t, dir, "add", ".")
+		gitfixture.RunGit(t, dir, "commit", "-q", "-m", "initial")
+		return dir
+	}
+
+	t.Run("untracked cache", func(t *testing.T) {
+		dir := newRepo(t)
+		gitfixture.WriteFile(t, filepath.Join(dir, ".git", "info", "exclude"), "*.log")
+		gitfixture.WriteFile(t, filepath.Join(dir, "cached"), "baa")
+		gitfixture.RunGit(t, dir, "update-index", "--untracked-cache")
+		gitfixture.RunGit(t, dir, "status", "--porcelain")
+
+		// Sneak in another file without changing the directory's
+		// mtime; only a cached listing misses it.
+		fi, err := os.Stat(dir)
+		require.NoError(t, err)
+		gitfixture.WriteFile(t, filepath.Join(dir, "sneaky"), "baa")
+		require.NoError(t, os.Chtimes(dir, fi.ModTime(), fi.ModTime()))
+
+		st, err := gitrepo.BuildWorktreeStatus(t.Context(), openWorktree(t, dir))
+		require.NoError(t, err)
+
+		assert.Equal(t, git.Untracked, requireStatus(t, st, "cached").Worktree)
+		assert.NotContains(t, st, "sneaky")
+	})
+
+	t.Run("fsmonitor", func(t *testing.T) {
+		m := newFakeFSMonitor(t)
+		m.report(t)
+
+		dir := newRepo(t)
+		gitfixture.RunGit(t, dir, "config", "core.fsmonitor", m.hook)
+		gitfixture.RunGit(t, dir, "update-index", "--fsmonitor")
+		gitfixture.RunGit(t, dir, "status", "--porcelain")
+
+		// Modify a file behind the monitor's back.
+		gitfixture.WriteFile(t, filepath.Join(dir, "foo"), "changed content")
+
+		st, err := gitrepo.BuildWorktreeStatus(t.Context(), openWorktree(t, dir))
+		require.NoError(t, err)
+
+		assert.True(t, st.IsClean(), "status:\n%s", st)
+
+		args, err := os.ReadFile(m.args)
+		require.NoError(t, err)
+		assert.Equal(t, "2 new-token\n", string(args))
+	})
+}
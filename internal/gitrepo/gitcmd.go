@@ -0,0 +1,56 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package gitrepo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// runGitFetch runs a `git fetch` with native git for what go-git can't
+// do: deepening a shallow clone and fetching objects by id.  The new
+// objects are made visible to the repository afterwards.
+func runGitFetch(ctx context.Context, cx *Context, stdin io.Reader, args ...string) error {
+	dotGit, ok := cx.DotGitPath()
+	if !ok {
+		return errors.New("repository is not stored on disk")
+	}
+	if _, err := exec.LookPath("git"); err != nil {
+		return fmt.Errorf("fetching requires the git command: %w", err)
+	}
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Env = append(os.Environ(), "GIT_DIR="+dotGit)
+	cmd.Stdin = stdin
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, bytes.TrimSpace(out))
+	}
+
+	// The fetched objects arrive in a new pack.
+	if st, ok := cx.Repository().Storer.(interface{ Reindex() }); ok {
+		st.Reindex()
+	}
+
+	return nil
+}
@@ -17,7 +17,10 @@
 package gitrepo
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"slices"
 
 	"github.com/go-git/go-git/v5"
@@ -31,6 +34,9 @@ type Guide struct {
 	// Scope describes the scope under which the Guide applies.
 	Scope Scope
 
+	// Scheme is the versioning scheme of Tags.  Nil means SemVer.
+	Scheme Scheme
+
 	// Commit is the commit at the given ref.
 	Commit *object.Commit
 
@@ -52,6 +58,10 @@ type Guide struct {
 	// no tag was found, Depth is the total number of commits
 	// reachable from Commit.
 	Depth int
+
+	// Shallow reports whether the repository is a shallow clone, so
+	// history beyond its boundary (and the tags there) was not seen.
+	Shallow bool
 }
 
 func (g Guide) String() string {
@@ -79,6 +89,13 @@ func (g Guide) IsPure() bool {
 	return len(g.Tags) > 0 && g.Depth == 0
 }
 
+// IsUnreliable reports whether no version tag was found in a shallow
+// clone.  The tag the version should derive from is most likely just
+// beyond the fetched history.
+func (g Guide) IsUnreliable() bool {
+	return g.Shallow && len(g.Tags) == 0
+}
+
 // HighestVersion returns the highest version tag in the Guide, or
 // nil if there is none.
 func (g Guide) HighestVersion() *VersionTag {
@@ -97,25 +114,176 @@ func (g Guide) HighestVersion() *VersionTag {
 // experimental work.  Tags that sit "in the future" of ref (ref is
 // a strict ancestor of the tag) are also skipped — they describe
 // versions that don't exist yet from ref's perspective.
-func BuildGuide(cx *Context, ref *plumbing.Reference, scope Scope) (*Guide, error) {
-	r := cx.Repository()
+func BuildGuide(ctx context.Context, cx *Context, ref *plumbing.Reference, scope Scope) (*Guide, error) {
+	if ref == nil {
+		return &Guide{Scope: scope, Scheme: cx.Scheme()}, nil
+	}
+
+	// Guides only depend on the refs of the repository, so results
+	// for an unchanged ref state are reused across invocations.
+	if guide := loadRefCache(cx).guide(cx, scope, ref.Hash()); guide != nil {
+		slog.DebugContext(ctx, "reuse cached guide", "commit", ref.Hash().String())
+		return guide, nil
+	}
+
+	b, err := NewGuideBuilder(ctx, cx, scope)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = b.Close() }()
+
+	guide, err := b.Build(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	// Collecting the tags may have updated the cache since.
+	if cache := loadRefCache(cx); cache != nil {
+		cache.putGuide(b, guide)
+		_ = cache.save()
+	}
+
+	return guide, nil
+}
+
+// TagCandidate is a version tag considered for a guide, see
+// ExplainGuide.
+type TagCandidate struct {
+	VersionTag
+
+	// Chosen reports whether the guide derives from the tag.
+	Chosen bool
 
+	// Skipped is why the tag was passed over.  Empty for chosen tags,
+	// and for tags not considered since a higher one was chosen.
+	Skipped string
+}
+
+// ExplainGuide builds the guide of ref like BuildGuide, bypassing the
+// cache, and also returns the version tags of scope it considered,
+// highest first, with the reason each was skipped.
+func ExplainGuide(ctx context.Context, cx *Context, ref *plumbing.Reference, scope Scope) (*Guide, []TagCandidate, error) {
 	if ref == nil {
-		return &Guide{Scope: scope}, nil
+		return &Guide{Scope: scope, Scheme: cx.Scheme()}, nil, nil
 	}
 
-	head, err := r.CommitObject(ref.Hash())
+	b, err := NewGuideBuilder(ctx, cx, scope)
 	if err != nil {
-		return nil, fmt.Errorf("resolve commit object: %w", err)
+		return nil, nil, err
+	}
+	defer func() { _ = b.Close() }()
+
+	b.skipped = map[string]string{}
+	guide, err := b.Build(ref)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	candidates := make([]TagCandidate, 0, len(b.tags))
+	for _, vt := range b.tags {
+		candidates = append(candidates, TagCandidate{
+			VersionTag: vt,
+			Chosen:     slices.ContainsFunc(guide.Tags, func(c VersionTag) bool { return c.TagName == vt.TagName }),
+			Skipped:    b.skipped[vt.TagName],
+		})
+	}
+	return guide, candidates, nil
+}
+
+// GuideBuilder builds guides for any number of commits within one
+// scope.  The version tags and the commit graph are loaded once and
+// shared by every Build call, which makes describing many commits
+// (e.g. walking history) much cheaper than repeated BuildGuide calls.
+type GuideBuilder struct {
+	repo  *git.Repository
+	scope Scope
+	graph *commitGraph
+
+	// coerceTags and scheme are the Context settings the tags were
+	// collected with.
+	coerceTags bool
+	scheme     Scheme
+
+	// tags holds the scope's version tags, highest first.
+	tags []VersionTag
+
+	// tips walks the history of every branch; built on first use.
+	// See walkBranchTips.
+	tips *ancestryWalker
+	// hasTips is false when the repository has no branches at all.
+	hasTips bool
+
+	// skipped records why selectReachableTag skipped tags, by tag
+	// name, when not nil.  See ExplainGuide.
+	skipped map[string]string
+}
+
+// skip logs why vt was skipped, and records it when explaining.
+func (b *GuideBuilder) skip(vt VersionTag, format string, args ...any) {
+	reason := fmt.Sprintf(format, args...)
+	slog.Debug("skip version tag", "tag", vt.TagName, "reason", reason)
+	if b.skipped != nil {
+		b.skipped[vt.TagName] = reason
 	}
+}
 
-	versionTagsIter, doneFn := IterVersionTags(cx, &scope)
+// NewGuideBuilder collects the version tags of scope.  The history
+// walks of every Build call stop with ctx's error once ctx is done.
+// Callers must Close the builder when done.
+func NewGuideBuilder(ctx context.Context, cx *Context, scope Scope) (*GuideBuilder, error) {
+	versionTagsIter, doneFn := IterVersionTags(ctx, cx, &scope)
 	versionTags := slices.SortedStableFunc(versionTagsIter, VersionTag.CompareDesc)
 	if err := doneFn(); err != nil {
 		return nil, fmt.Errorf("collect tags: %w", err)
 	}
-	if len(versionTags) > 0 {
-		guide, err := selectReachableTag(r, head, scope, versionTags)
+	slog.DebugContext(ctx, "collect version tags", "scope", scope.String(), "tags", len(versionTags))
+
+	return &GuideBuilder{
+		repo:  cx.Repository(),
+		scope: scope,
+		graph: newCommitGraph(ctx, cx),
+		tags:  versionTags,
+
+		coerceTags: cx.coerceTags,
+		scheme:     cx.Scheme(),
+	}, nil
+}
+
+// Close releases the resources held by the builder.
+func (b *GuideBuilder) Close() error {
+	return b.graph.Close()
+}
+
+// IsShallowBoundary reports whether h is a commit of a shallow clone
+// whose parents were not fetched.
+func (b *GuideBuilder) IsShallowBoundary(h plumbing.Hash) bool {
+	return b.graph.shallow[h]
+}
+
+// Build describes ref as BuildGuide does, using the builder's tags.
+func (b *GuideBuilder) Build(ref *plumbing.Reference) (*Guide, error) {
+	if ref == nil {
+		return &Guide{Scope: b.scope, Scheme: b.scheme}, nil
+	}
+
+	head, err := b.repo.CommitObject(ref.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("resolve commit object: %w", err)
+	}
+
+	ancestry, err := b.graph.newAncestryWalker(head.Hash)
+	if err != nil {
+		return nil, fmt.Errorf("walk history: %w", err)
+	}
+
+	if len(b.tags) > 0 {
+		if b.tips == nil {
+			if err := b.walkBranchTips(); err != nil {
+				return nil, fmt.Errorf("walk branch tips: %w", err)
+			}
+		}
+
+		guide, err := b.selectReachableTag(head, ancestry)
 		if err != nil {
 			return nil, fmt.Errorf("select reachable tag: %w", err)
 		}
@@ -126,75 +294,96 @@ func BuildGuide(cx *Context, ref *plumbing.Reference, scope Scope) (*Guide, erro
 
 	// No reachable version tag.  Depth becomes "everything reachable
 	// from ref" so callers can tell whether any history exists.
-	depth, err := countReachable(head)
+	depth, err := ancestry.count()
 	if err != nil {
 		return nil, fmt.Errorf("count reachable commits: %w", err)
 	}
 
 	guide := &Guide{
-		Scope:  scope,
-		Commit: head,
-		Depth:  depth,
+		Scope:   b.scope,
+		Scheme:  b.scheme,
+		Commit:  head,
+		Depth:   depth,
+		Shallow: b.graph.isShallow(),
 	}
 
 	return guide, nil
 }
 
-// selectReachableTag picks the highest-semver tag from tm whose
-// commit shares non-trivial history with head, applying the
-// tip-filter and the "future tag" guard.  Returns nil when no tag
-// qualifies (caller should fall back to the no-tag path).
-func selectReachableTag(
-	repo *git.Repository,
-	head *object.Commit,
-	scope Scope,
-	tags []VersionTag,
-) (*Guide, error) {
-	tipSet, err := buildTipSet(repo)
-	if err != nil {
-		return nil, fmt.Errorf("build tip set: %w", err)
-	}
-
-	for _, vtc := range tags {
+// selectReachableTag picks the highest-semver tag whose commit shares
+// non-trivial history with head, applying the tip-filter and the
+// "future tag" guard.  Returns nil when no tag qualifies (caller
+// should fall back to the no-tag path).
+//
+// Like `git describe`, the common case of a tag in head's own
+// ancestry is answered by a single walk from head that only goes as
+// deep as the tagged commits; only tags on side branches need a
+// merge-base computation.
+func (b *GuideBuilder) selectReachableTag(head *object.Commit, ancestry *ancestryWalker) (*Guide, error) {
+	for _, vtc := range b.tags {
 		// Skip tags on stranded commits when we have a tip-set to
-		// compare against.  An empty tip-set means the repo has no
-		// branches at all; fall back to considering every tag in
-		// that case.
-		if len(tipSet) > 0 && !tipSet[vtc.CommitHash] {
-			continue
+		// compare against.  No tips means the repo has no branches
+		// at all; fall back to considering every tag in that case.
+		if b.hasTips {
+			onBranch, err := b.tips.contains(vtc.CommitHash)
+			if err != nil {
+				return nil, fmt.Errorf("check tag commit %s: %w", vtc.CommitHash, err)
+			}
+			if !onBranch {
+				b.skip(vtc, "stranded: no branch contains commit %s", vtc.CommitHash)
+				continue
+			}
 		}
 
-		tagCommit, err := repo.CommitObject(vtc.CommitHash)
+		tagCommit, err := b.repo.CommitObject(vtc.CommitHash)
 		if err != nil {
 			return nil, fmt.Errorf("resolve tag commit %s: %w", vtc.CommitHash, err)
 		}
 
-		bases, err := head.MergeBase(tagCommit)
+		isAncestor, err := ancestry.contains(vtc.CommitHash)
 		if err != nil {
-			return nil, fmt.Errorf("compute merge-base: %w", err)
+			return nil, fmt.Errorf("check tag commit %s: %w", vtc.CommitHash, err)
 		}
-		if len(bases) == 0 {
-			continue
-		}
-		mergeBase := bases[0]
 
-		// Skip tags from head's "future": head is a strict ancestor
-		// of the tag, not at it.
-		if mergeBase.Hash == head.Hash && head.Hash != vtc.CommitHash {
-			continue
+		mergeBase := tagCommit
+		if !isAncestor {
+			bases, err := head.MergeBase(tagCommit)
+			switch {
+			case errors.Is(err, plumbing.ErrObjectNotFound) && b.graph.isShallow():
+				// The histories only meet beyond the shallow boundary.
+				b.skip(vtc, "unreachable: its history meets %s beyond the shallow boundary", head.Hash)
+				continue
+			case err != nil:
+				return nil, fmt.Errorf("compute merge-base: %w", err)
+			}
+			if len(bases) == 0 {
+				b.skip(vtc, "unreachable: no merge base with %s", head.Hash)
+				continue
+			}
+			mergeBase = bases[0]
+
+			// Skip tags from head's "future": head is a strict
+			// ancestor of the tag, not at it.
+			if mergeBase.Hash == head.Hash {
+				b.skip(vtc, "in the future: %s is an ancestor of its commit", head.Hash)
+				continue
+			}
 		}
 
-		depth, err := commitsAhead(head, mergeBase)
+		depth, err := b.graph.countAhead(head.Hash, mergeBase.Hash)
 		if err != nil {
 			return nil, fmt.Errorf("count divergence: %w", err)
 		}
+		slog.Debug("select version tag", "tag", vtc.TagName, "merge_base", mergeBase.Hash.String(), "depth", depth)
 
 		return &Guide{
-			Scope:     scope,
+			Scope:     b.scope,
+			Scheme:    b.scheme,
 			Commit:    head,
-			Tags:      collectSameVersion(tags, vtc.VersionSpec),
+			Tags:      collectSameVersion(b.tags, vtc.VersionSpec),
 			MergeBase: mergeBase,
 			Depth:     depth,
+			Shallow:   b.graph.isShallow(),
 		}, nil
 	}
 
@@ -211,80 +400,10 @@ func collectSameVersion(candidates []VersionTag, spec VersionSpec) []VersionTag
 	return out
 }
 
-// buildTipSet returns the set of commit hashes reachable from any
-// local branch or remote-tracking ref.  Remote-tracking refs are
-// included because typical CI checkouts and fresh clones leave
-// every non-checked-out branch as `refs/remotes/origin/*`; ignoring
-// them would wrongly classify legitimate release-branch tags as
-// stranded.  An empty set means the repo has no branches at all,
-// in which case callers should treat every tag as in-scope.
-func buildTipSet(repo *git.Repository) (map[plumbing.Hash]bool, error) {
-	set := map[plumbing.Hash]bool{}
-
-	refIter, err := repo.References()
-	if err != nil {
-		return nil, fmt.Errorf("list references: %w", err)
-	}
-
-	err = refIter.ForEach(func(ref *plumbing.Reference) error {
-		name := ref.Name()
-		if !name.IsBranch() && !name.IsRemote() {
-			return nil
-		}
-		commit, cErr := repo.CommitObject(ref.Hash())
-		if cErr != nil {
-			// Refs that don't resolve to a commit (broken or
-			// symbolic refs) don't contribute to reachability.
-			return nil
-		}
-		iter := object.NewCommitPreorderIter(commit, set, nil)
-		return iter.ForEach(func(c *object.Commit) error {
-			set[c.Hash] = true
-			return nil
-		})
-	})
-	if err != nil {
-		return nil, fmt.Errorf("walk references: %w", err)
-	}
-
-	return set, nil
-}
-
-// commitsAhead counts commits reachable from head but not from
-// mergeBase. Same as `git rev-list --count mergeBase..head`.
-func commitsAhead(head, mergeBase *object.Commit) (int, error) {
-	if head.Hash == mergeBase.Hash {
-		return 0, nil
-	}
-
-	excluded := map[plumbing.Hash]bool{}
-	mergeBaseIter := object.NewCommitPreorderIter(mergeBase, nil, nil)
-	if err := mergeBaseIter.ForEach(func(c *object.Commit) error {
-		excluded[c.Hash] = true
-		return nil
-	}); err != nil {
-		return 0, fmt.Errorf("walk merge-base ancestors: %w", err)
-	}
-
-	count := 0
-	headIter := object.NewCommitPreorderIter(head, excluded, nil)
-	if err := headIter.ForEach(func(c *object.Commit) error {
-		count++
-		return nil
-	}); err != nil {
-		return 0, fmt.Errorf("walk head ancestors: %w", err)
-	}
-	return count, nil
-}
-
-// countReachable counts every commit reachable from the head, including
-// the head itself.
-func countReachable(head *object.Commit) (int, error) {
-	count := 0
-	iter := object.NewCommitPreorderIter(head, nil, nil)
-	err := iter.ForEach(func(c *object.Commit) error {
-		count++
-		return nil
-	})
-	return count, err
+// walkBranchTips prepares the walk over the commits reachable from
+// any branch.  See commitGraph.walkBranchTips.
+func (b *GuideBuilder) walkBranchTips() error {
+	var err error
+	b.tips, b.hasTips, err = b.graph.walkBranchTips(b.repo)
+	return err
 }
@@ -43,7 +43,7 @@ func TestBuildGuide_ScopedTagSelection(t *testing.T) {
 	head := gitfixture.Head(t, cx)
 
 	t.Run("root-scope", func(t *testing.T) {
-		guide, err := gitrepo.BuildGuide(cx, head, gitrepo.RootScope())
+		guide, err := gitrepo.BuildGuide(t.Context(), cx, head, gitrepo.RootScope())
 		require.NoError(t, err)
 
 		vt := guide.HighestVersion()
@@ -54,7 +54,7 @@ func TestBuildGuide_ScopedTagSelection(t *testing.T) {
 	})
 
 	t.Run("module-scope", func(t *testing.T) {
-		guide, err := gitrepo.BuildGuide(cx, head, mustScope(t, "mod"))
+		guide, err := gitrepo.BuildGuide(t.Context(), cx, head, mustScope(t, "mod"))
 		require.NoError(t, err)
 
 		vt := guide.HighestVersion()
@@ -91,7 +91,7 @@ func TestBuildGuide_SideBranchTag(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, c.Hash, head.Hash())
 
-	guide, err := gitrepo.BuildGuide(repo, head, gitrepo.RootScope())
+	guide, err := gitrepo.BuildGuide(t.Context(), repo, head, gitrepo.RootScope())
 	require.NoError(t, err)
 
 	vt := guide.HighestVersion()
@@ -143,7 +143,7 @@ func TestBuildGuide_MultipleReleaseBranches(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, dCommit.Hash, head.Hash())
 
-	guide, err := gitrepo.BuildGuide(repo, head, gitrepo.RootScope())
+	guide, err := gitrepo.BuildGuide(t.Context(), repo, head, gitrepo.RootScope())
 	require.NoError(t, err)
 
 	require.Len(t, guide.Tags, 1)
@@ -174,7 +174,7 @@ func TestBuildGuide_AncestorOfTag(t *testing.T) {
 	// hasn't been "released" yet from there.
 	earlyRef := plumbing.NewHashReference(plumbing.HEAD, earlyHash)
 
-	guide, err := gitrepo.BuildGuide(repo, earlyRef, gitrepo.RootScope())
+	guide, err := gitrepo.BuildGuide(t.Context(), repo, earlyRef, gitrepo.RootScope())
 	require.NoError(t, err)
 
 	assert.Empty(t, guide.Tags, "tag in HEAD's future must not be picked")
@@ -205,7 +205,7 @@ func TestBuildGuide_StrandedTagFiltered(t *testing.T) {
 	_, err = repo.Repository().CreateTag("v0.1.0", mainHead.Hash(), nil)
 	require.NoError(t, err)
 
-	guide, err := gitrepo.BuildGuide(repo, mainHead, gitrepo.RootScope())
+	guide, err := gitrepo.BuildGuide(t.Context(), repo, mainHead, gitrepo.RootScope())
 	require.NoError(t, err)
 
 	require.Len(t, guide.Tags, 1)
@@ -213,6 +213,49 @@ func TestBuildGuide_StrandedTagFiltered(t *testing.T) {
 		"stranded v9.0.0 must not outrank reachable v0.1.0")
 }
 
+// TestExplainGuide verifies that the candidates come highest first,
+// with the reason each tag above the chosen one was skipped.
+func TestExplainGuide(t *testing.T) {
+	repo := gitfixture.RepoWithOneCommitNoTagsClean(t) // A on main
+	gitfixture.CreateTag(t, repo, "v0.1.0")
+
+	// A stranded tag on a deleted branch.
+	gitfixture.Checkout(t, repo, "experimental", true)
+	gitfixture.CommitFile(t, repo, "exp.txt", "experiment")
+	gitfixture.CreateTag(t, repo, "v9.0.0")
+	gitfixture.Checkout(t, repo, "main", false)
+	require.NoError(t, repo.Repository().Storer.RemoveReference(
+		plumbing.NewBranchReferenceName("experimental"),
+	))
+
+	// A tag in the future of the described commit.
+	early := gitfixture.CommitFile(t, repo, "b.txt", "b")
+	gitfixture.CreateTag(t, repo, "v0.2.0")
+	gitfixture.CommitFile(t, repo, "c.txt", "c")
+	gitfixture.CreateTag(t, repo, "v1.0.0")
+
+	ref := plumbing.NewHashReference(plumbing.HEAD, early.Hash)
+	guide, candidates, err := gitrepo.ExplainGuide(t.Context(), repo, ref, gitrepo.RootScope())
+	require.NoError(t, err)
+
+	require.Len(t, guide.Tags, 1)
+	assert.Equal(t, "v0.2.0", guide.Tags[0].TagName)
+
+	require.Len(t, candidates, 4)
+	var names []string
+	for _, c := range candidates {
+		names = append(names, c.TagName)
+	}
+	assert.Equal(t, []string{"v9.0.0", "v1.0.0", "v0.2.0", "v0.1.0"}, names)
+
+	assert.Contains(t, candidates[0].Skipped, "stranded")
+	assert.Contains(t, candidates[1].Skipped, "in the future")
+	assert.True(t, candidates[2].Chosen)
+	assert.Empty(t, candidates[2].Skipped)
+	assert.False(t, candidates[3].Chosen)
+	assert.Empty(t, candidates[3].Skipped)
+}
+
 // TestFindLatestVersionTagOnRemoteTrackingBranch covers the typical
 // CI / fresh-clone setup: only the checked-out branch exists locally;
 // every other branch is present as `refs/remotes/origin/*`.  A
@@ -251,7 +294,7 @@ func TestBuildGuide_RemoteTrackingBranch(t *testing.T) {
 	require.Equal(t, c.Hash, head.Hash())
 
 	// Act.
-	guide, err := gitrepo.BuildGuide(repo, head, gitrepo.RootScope())
+	guide, err := gitrepo.BuildGuide(t.Context(), repo, head, gitrepo.RootScope())
 	require.NoError(t, err)
 
 	// Assert: v0.5.0 is reachable via origin/release/0.5 and must not
@@ -263,3 +306,66 @@ func TestBuildGuide_RemoteTrackingBranch(t *testing.T) {
 	assert.Equal(t, bCommit.Hash, guide.MergeBase.Hash)
 	assert.Equal(t, 1, guide.Depth)
 }
+
+func TestBuildGuide_CoerceTags(t *testing.T) {
+	// Arrange: v1.2 is not a version tag unless coerced.
+	cx := gitfixture.RepoWithOneCommitNoTagsClean(t)
+	gitfixture.CreateTag(t, cx, "v0.9.0")
+	gitfixture.CommitFile(t, cx, "bar", "baa")
+	gitfixture.CreateTag(t, cx, "v1.2")
+	gitfixture.CommitFile(t, cx, "baz", "baa")
+	head := gitfixture.Head(t, cx)
+
+	// Act: Build both guides in turn; the cached guide of one mode
+	// must not leak into the other.
+	strict, err := gitrepo.BuildGuide(t.Context(), cx, head, gitrepo.RootScope())
+	require.NoError(t, err)
+
+	cx.SetCoerceTags(true)
+	coerced, err := gitrepo.BuildGuide(t.Context(), cx, head, gitrepo.RootScope())
+	require.NoError(t, err)
+
+	// Assert
+	require.NotNil(t, strict.HighestVersion())
+	assert.Equal(t, "v0.9.0", strict.HighestVersion().TagName)
+	assert.False(t, strict.HighestVersion().Coerced)
+
+	require.NotNil(t, coerced.HighestVersion())
+	assert.Equal(t, "v1.2", coerced.HighestVersion().TagName)
+	assert.True(t, coerced.HighestVersion().Coerced)
+	assert.Equal(t, "v1.2.0", gitrepo.LatestSpec(coerced).String())
+	assert.Equal(t, 1, coerced.Depth)
+}
+
+func TestBuildGuide_Scheme(t *testing.T) {
+	// Arrange: a semantic and a calendar version on different commits.
+	cx := gitfixture.RepoWithOneCommitNoTagsClean(t)
+	gitfixture.CreateTag(t, cx, "2026.09.1")
+	gitfixture.CommitFile(t, cx, "bar", "baa")
+	gitfixture.CreateTag(t, cx, "v1.0.0")
+	gitfixture.CommitFile(t, cx, "baz", "baa")
+	head := gitfixture.Head(t, cx)
+
+	scheme, err := gitrepo.ParseCalVer("YYYY.0M.MICRO")
+	require.NoError(t, err)
+
+	// Act: Build both guides in turn; the cached guide of one scheme
+	// must not leak into the other.
+	semantic, err := gitrepo.BuildGuide(t.Context(), cx, head, gitrepo.RootScope())
+	require.NoError(t, err)
+
+	cx.SetScheme(scheme)
+	calendar, err := gitrepo.BuildGuide(t.Context(), cx, head, gitrepo.RootScope())
+	require.NoError(t, err)
+
+	// Assert: the zero-padded month makes 2026.09.1 no semantic
+	// version, and v1.0.0 is no calendar version.
+	require.NotNil(t, semantic.HighestVersion())
+	assert.Equal(t, "v1.0.0", semantic.HighestVersion().TagName)
+	assert.Equal(t, 1, semantic.Depth)
+
+	require.NotNil(t, calendar.HighestVersion())
+	assert.Equal(t, "2026.09.1", calendar.HighestVersion().TagName)
+	assert.Equal(t, "2026.09.1", gitrepo.LatestSpec(calendar).String())
+	assert.Equal(t, 2, calendar.Depth)
+}
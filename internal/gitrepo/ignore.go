@@ -35,27 +35,17 @@ type ignoreMatcher struct {
 	ignoreCase bool
 }
 
-func loadIgnoreMatcher(cx *Context) (*ignoreMatcher, error) {
+func loadIgnoreMatcher(cx *Context, cfg effectiveConfig) (*ignoreMatcher, error) {
 	wtFsys, err := cx.LoadWorktreeFilesystem()
 	if err != nil {
 		return nil, fmt.Errorf("load worktree filesystem: %w", err)
 	}
 
-	cfg, err := loadEffectiveConfig(cx)
-	if err != nil {
-		return nil, err
-	}
-
 	var patterns []gitignore.Pattern
 
-	var excludesFile string
-	if cfg.excludesFile == nil {
-		excludesFile, err = defaultExcludesFilePath()
-		if err != nil {
-			return nil, err
-		}
-	} else {
-		excludesFile = *cfg.excludesFile
+	excludesFile, err := resolveExcludesFile(cfg)
+	if err != nil {
+		return nil, err
 	}
 
 	if excludesFile != "" {
@@ -107,6 +97,15 @@ func (m *ignoreMatcher) Match(path string, isDir bool) bool {
 	return m.matcher.Match(parts, isDir)
 }
 
+// resolveExcludesFile returns the path of core.excludesFile, or of
+// its XDG default when unset.
+func resolveExcludesFile(cfg effectiveConfig) (string, error) {
+	if cfg.excludesFile != nil {
+		return *cfg.excludesFile, nil
+	}
+	return defaultExcludesFilePath()
+}
+
 func readExcludesFilePatterns(
 	wtFsys billy.Filesystem,
 	p string,
@@ -0,0 +1,417 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package gitrepo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+var errMalformedIndex = errors.New("malformed index")
+
+// Index entry layout constants, see git's Documentation/gitformat-index.
+const (
+	indexHeaderSize     = 12
+	indexEntryFixedSize = 62
+	indexEntryExtended  = 0x4000
+	indexStatDataSize   = 36
+	indexHashSize       = len(plumbing.ZeroHash)
+)
+
+// indexExtensions holds the index extensions that go-git's decoder
+// skips, together with the number of entries they refer to.
+type indexExtensions struct {
+	entryCount int
+
+	// fsmonitor is the FSMN extension, nil when absent.
+	fsmonitor *fsmonitorState
+
+	// untracked is the UNTR extension, nil when absent.
+	untracked *untrackedCache
+}
+
+// fsmonitorState is the FSMN extension: the token (or, for version 1,
+// the timestamp) of the last fsmonitor query, and which entries were
+// not known to be unchanged at that point.
+type fsmonitorState struct {
+	version int
+	token   string
+	dirty   *ewahBitmap
+}
+
+// untrackedCache is the UNTR extension: the untracked files git found
+// per directory, with the data needed to tell whether each list is
+// still current.
+type untrackedCache struct {
+	ident           string
+	infoExcludeOID  plumbing.Hash
+	excludesFileOID plumbing.Hash
+	excludeFileName string
+	root            *untrackedDir
+}
+
+type untrackedDir struct {
+	name      string
+	untracked []string
+	dirs      []*untrackedDir
+
+	// valid is set when the untracked list and mtime are usable.
+	valid     bool
+	checkOnly bool
+	mtime     time.Time
+
+	// excludeOID is the hash of the directory's .gitignore at the
+	// time, zero if it had none.
+	excludeOID plumbing.Hash
+}
+
+// readIndexExtensions reads the FSMN and UNTR extensions from the
+// index file.  Returns nil without error when there is no index.
+// Split indexes are not supported and yield no extensions.
+func readIndexExtensions(fsys billy.Filesystem) (*indexExtensions, error) {
+	f, err := fsys.Open("index")
+	switch {
+	case isErrNotExist(err):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseIndexExtensions(data)
+}
+
+func parseIndexExtensions(data []byte) (*indexExtensions, error) {
+	if len(data) < indexHeaderSize+indexHashSize || !bytes.Equal(data[:4], []byte("DIRC")) {
+		return nil, errMalformedIndex
+	}
+	version := binary.BigEndian.Uint32(data[4:])
+	count := int(binary.BigEndian.Uint32(data[8:]))
+	if version < 2 || version > 4 {
+		return nil, fmt.Errorf("unsupported index version %d", version)
+	}
+
+	// The trailing checksum is not part of any extension.
+	body := data[:len(data)-indexHashSize]
+	pos := indexHeaderSize
+
+	for range count {
+		n, err := indexEntrySize(body[pos:], version)
+		if err != nil {
+			return nil, err
+		}
+		pos += n
+	}
+
+	ext := &indexExtensions{entryCount: count}
+	for pos < len(body) {
+		if len(body)-pos < 8 {
+			return nil, errMalformedIndex
+		}
+		sig := string(body[pos : pos+4])
+		size := int(binary.BigEndian.Uint32(body[pos+4:]))
+		pos += 8
+		if size > len(body)-pos {
+			return nil, errMalformedIndex
+		}
+		payload := body[pos : pos+size]
+		pos += size
+
+		var err error
+		switch sig {
+		case "link":
+			// Split index: entry positions refer to the shared
+			// index, which is not read here.
+			return &indexExtensions{entryCount: count}, nil
+		case "FSMN":
+			ext.fsmonitor, err = parseFSMonitorExtension(payload)
+		case "UNTR":
+			ext.untracked, err = parseUntrackedExtension(payload)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse %s extension: %w", sig, err)
+		}
+	}
+
+	return ext, nil
+}
+
+// indexEntrySize returns the on-disk size of the entry at the start
+// of data.
+func indexEntrySize(data []byte, version uint32) (int, error) {
+	if len(data) < indexEntryFixedSize {
+		return 0, errMalformedIndex
+	}
+
+	fixed := indexEntryFixedSize
+	flags := binary.BigEndian.Uint16(data[60:])
+	if version >= 3 && flags&indexEntryExtended != 0 {
+		fixed += 2
+	}
+	if len(data) < fixed {
+		return 0, errMalformedIndex
+	}
+
+	rest := data[fixed:]
+	if version == 4 {
+		// Prefix-compressed name: a varint then a NUL-terminated
+		// suffix, without padding.
+		_, n, err := decodeGitVarint(rest)
+		if err != nil {
+			return 0, err
+		}
+		end := bytes.IndexByte(rest[n:], 0)
+		if end < 0 {
+			return 0, errMalformedIndex
+		}
+		return fixed + n + end + 1, nil
+	}
+
+	nameLen := bytes.IndexByte(rest, 0)
+	if nameLen < 0 {
+		return 0, errMalformedIndex
+	}
+
+	// NUL padded to a multiple of eight bytes, at least one NUL.
+	size := (fixed + nameLen + 8) &^ 7
+	if size > len(data) {
+		return 0, errMalformedIndex
+	}
+	return size, nil
+}
+
+// decodeGitVarint decodes git's variable width integer encoding
+// (varint.c) and returns the value and the number of bytes read.
+func decodeGitVarint(data []byte) (uint64, int, error) {
+	if len(data) == 0 {
+		return 0, 0, errMalformedIndex
+	}
+
+	c := data[0]
+	val := uint64(c & 0x7f)
+	n := 1
+	for c&0x80 != 0 {
+		if n >= len(data) || n > 9 {
+			return 0, 0, errMalformedIndex
+		}
+		c = data[n]
+		n++
+		val = ((val + 1) << 7) | uint64(c&0x7f)
+	}
+
+	return val, n, nil
+}
+
+func parseFSMonitorExtension(data []byte) (*fsmonitorState, error) {
+	if len(data) < 4 {
+		return nil, errMalformedIndex
+	}
+	st := &fsmonitorState{version: int(binary.BigEndian.Uint32(data))}
+	data = data[4:]
+
+	switch st.version {
+	case 1:
+		if len(data) < 8 {
+			return nil, errMalformedIndex
+		}
+		st.token = fmt.Sprint(binary.BigEndian.Uint64(data))
+		data = data[8:]
+	case 2:
+		end := bytes.IndexByte(data, 0)
+		if end < 0 {
+			return nil, errMalformedIndex
+		}
+		st.token = string(data[:end])
+		data = data[end+1:]
+	default:
+		return nil, fmt.Errorf("unsupported fsmonitor extension version %d", st.version)
+	}
+
+	if len(data) < 4 {
+		return nil, errMalformedIndex
+	}
+	size := int(binary.BigEndian.Uint32(data))
+	data = data[4:]
+	if size > len(data) {
+		return nil, errMalformedIndex
+	}
+
+	dirty, _, err := decodeEWAH(data[:size])
+	if err != nil {
+		return nil, err
+	}
+	st.dirty = dirty
+
+	return st, nil
+}
+
+// untrackedReader is a cursor over the UNTR extension payload.
+type untrackedReader struct {
+	data []byte
+	err  error
+}
+
+func (r *untrackedReader) fail() {
+	if r.err == nil {
+		r.err = errMalformedIndex
+	}
+}
+
+func (r *untrackedReader) bytes(n int) []byte {
+	if r.err != nil || n < 0 || n > len(r.data) {
+		r.fail()
+		return make([]byte, max(n, 0))
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+func (r *untrackedReader) varint() int {
+	if r.err != nil {
+		return 0
+	}
+	v, n, err := decodeGitVarint(r.data)
+	if err != nil || v > uint64(len(r.data)) {
+		// No count can exceed the number of remaining bytes.
+		r.fail()
+		return 0
+	}
+	r.data = r.data[n:]
+	return int(v)
+}
+
+func (r *untrackedReader) cstring() string {
+	if r.err != nil {
+		return ""
+	}
+	end := bytes.IndexByte(r.data, 0)
+	if end < 0 {
+		r.fail()
+		return ""
+	}
+	s := string(r.data[:end])
+	r.data = r.data[end+1:]
+	return s
+}
+
+func (r *untrackedReader) hash() plumbing.Hash {
+	var h plumbing.Hash
+	copy(h[:], r.bytes(indexHashSize))
+	return h
+}
+
+func (r *untrackedReader) ewah() *ewahBitmap {
+	if r.err != nil {
+		return &ewahBitmap{}
+	}
+	b, rest, err := decodeEWAH(r.data)
+	if err != nil {
+		r.err = err
+		return &ewahBitmap{}
+	}
+	r.data = rest
+	return b
+}
+
+// dir reads one directory block and its children in depth-first
+// order, appending every block to all.
+func (r *untrackedReader) dir(all *[]*untrackedDir, depth int) *untrackedDir {
+	if depth > 4096 {
+		r.fail()
+		return &untrackedDir{}
+	}
+
+	untrackedCount := r.varint()
+	dirCount := r.varint()
+
+	d := &untrackedDir{name: r.cstring()}
+	*all = append(*all, d)
+
+	for range untrackedCount {
+		d.untracked = append(d.untracked, r.cstring())
+	}
+	for range dirCount {
+		if r.err != nil {
+			break
+		}
+		d.dirs = append(d.dirs, r.dir(all, depth+1))
+	}
+
+	return d
+}
+
+func parseUntrackedExtension(data []byte) (*untrackedCache, error) {
+	r := &untrackedReader{data: data}
+	uc := &untrackedCache{}
+
+	uc.ident = string(r.bytes(r.varint()))
+	r.bytes(2*indexStatDataSize + 4) // exclude file stat data, dir flags
+	uc.infoExcludeOID = r.hash()
+	uc.excludesFileOID = r.hash()
+	uc.excludeFileName = r.cstring()
+
+	if r.varint() == 0 {
+		return uc, r.err
+	}
+
+	var all []*untrackedDir
+	uc.root = r.dir(&all, 0)
+
+	valid := r.ewah()
+	checkOnly := r.ewah()
+	hashValid := r.ewah()
+
+	for _, i := range valid.ones() {
+		if i >= len(all) {
+			r.fail()
+			break
+		}
+		sd := r.bytes(indexStatDataSize)
+		all[i].valid = true
+		all[i].mtime = time.Unix(int64(binary.BigEndian.Uint32(sd[8:])), int64(binary.BigEndian.Uint32(sd[12:])))
+	}
+	for _, i := range checkOnly.ones() {
+		if i < len(all) {
+			all[i].checkOnly = true
+		}
+	}
+	for _, i := range hashValid.ones() {
+		if i >= len(all) {
+			r.fail()
+			break
+		}
+		all[i].excludeOID = r.hash()
+	}
+
+	if r.err != nil {
+		return nil, r.err
+	}
+	return uc, nil
+}
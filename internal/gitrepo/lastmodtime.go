@@ -17,6 +17,7 @@
 package gitrepo
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io/fs"
@@ -137,14 +138,14 @@ func (e *DirtyEntry) ModTime() time.Time             { return e.mtime }
 // IterDirtyEntries walks the worktree status and returns the dirty entries
 // with their effective mtimes (using the deleted-ancestor walk for paths
 // that no longer exist), along with the index file's mtime if available.
-func IterDirtyEntries(cx *Context) (iter.Seq[DirtyEntry], func() error) {
+func IterDirtyEntries(ctx context.Context, cx *Context) (iter.Seq[DirtyEntry], func() error) {
 	return xit.Perform(func(yield func(DirtyEntry) bool) error {
 		wtFsys, err := cx.LoadWorktreeFilesystem()
 		if err != nil {
 			return fmt.Errorf("load worktree: %w", err)
 		}
 
-		st, err := BuildWorktreeStatus(cx)
+		st, mtimes, err := buildWorktreeStatus(ctx, cx, true)
 		if err != nil {
 			return fmt.Errorf("build worktree status: %w", err)
 		}
@@ -154,9 +155,14 @@ func IterDirtyEntries(cx *Context) (iter.Seq[DirtyEntry], func() error) {
 				continue
 			}
 
-			mtime, err := findMTimePath(wtFsys, fp)
-			if err != nil {
-				return fmt.Errorf("find mtime for %q: %w", fp, err)
+			// Reuse the mtime seen while building the status
+			// rather than stat'ing the path again.
+			mtime, ok := mtimes[fp]
+			if !ok {
+				mtime, err = findMTimePath(wtFsys, fp)
+				if err != nil {
+					return fmt.Errorf("find mtime for %q: %w", fp, err)
+				}
 			}
 
 			if !yield(DirtyEntry{
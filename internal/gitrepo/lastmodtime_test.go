@@ -49,7 +49,7 @@ func TestFindDirtyEntries(t *testing.T) {
 	t.Run("clean repo returns no entries", func(t *testing.T) {
 		cx := gitfixture.RepoWithOneCommitNoTagsClean(t)
 
-		entries, err := collectErr(gitrepo.IterDirtyEntries(cx))
+		entries, err := collectErr(gitrepo.IterDirtyEntries(t.Context(), cx))
 		require.NoError(t, err)
 
 		assert.Empty(t, entries)
@@ -64,7 +64,7 @@ func TestFindDirtyEntries(t *testing.T) {
 		require.NoError(t, err)
 		after := inf.ModTime().UTC()
 
-		entries, err := collectErr(gitrepo.IterDirtyEntries(cx))
+		entries, err := collectErr(gitrepo.IterDirtyEntries(t.Context(), cx))
 		require.NoError(t, err)
 
 		assert.WithinDuration(t, after, maxEntryMTime(entries), 100*time.Millisecond)
@@ -80,7 +80,7 @@ func TestFindDirtyEntries(t *testing.T) {
 		require.NoError(t, err)
 		after := inf.ModTime().UTC()
 
-		entries, err := collectErr(gitrepo.IterDirtyEntries(cx))
+		entries, err := collectErr(gitrepo.IterDirtyEntries(t.Context(), cx))
 		require.NoError(t, err)
 
 		assert.WithinDuration(t, after, maxEntryMTime(entries), 100*time.Millisecond)
@@ -103,7 +103,7 @@ func TestFindDirtyEntries(t *testing.T) {
 		// Optional sanity check: never goes backwards.
 		assert.False(t, after.Before(before), "mtime should not go backwards")
 
-		entries, err := collectErr(gitrepo.IterDirtyEntries(cx))
+		entries, err := collectErr(gitrepo.IterDirtyEntries(t.Context(), cx))
 		require.NoError(t, err)
 
 		assert.WithinDuration(t, after, maxEntryMTime(entries), 100*time.Millisecond)
@@ -121,7 +121,7 @@ func TestFindDirtyEntries(t *testing.T) {
 		require.NoError(t, err)
 		after := inf.ModTime().UTC()
 
-		entries, err := collectErr(gitrepo.IterDirtyEntries(cx))
+		entries, err := collectErr(gitrepo.IterDirtyEntries(t.Context(), cx))
 		require.NoError(t, err)
 
 		assert.WithinDuration(t, after, maxEntryMTime(entries), 100*time.Millisecond)
@@ -0,0 +1,355 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package gitrepo
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// Severity ranks a lint finding.
+type Severity int
+
+const (
+	// SeverityWarning marks tags that are confusing but don't change
+	// which version is derived.
+	SeverityWarning Severity = iota + 1
+
+	// SeverityError marks tags that make the derived version depend
+	// on tie-breakers or history layout rather than on intent.
+	SeverityError
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	}
+	return ""
+}
+
+// Check names a lint check.
+type Check string
+
+const (
+	// CheckDuplicateVersion finds several tags naming the same
+	// version of a scope, like "v1.0.0" and "1.0.0".
+	CheckDuplicateVersion Check = "duplicate-version"
+
+	// CheckStrandedTag finds version tags on commits no branch
+	// reaches.  They are ignored when deriving versions.
+	CheckStrandedTag Check = "stranded-tag"
+
+	// CheckNonMonotonic finds version tags on commits that descend
+	// from a commit tagged with a higher version of the same scope.
+	CheckNonMonotonic Check = "non-monotonic"
+
+	// CheckMixedPrefixes finds scopes whose tags use several
+	// prefixes, like "v1.0.0" and "1.1.0".
+	CheckMixedPrefixes Check = "mixed-prefixes"
+
+	// CheckNearVersion finds tags that look like versions but aren't
+	// version tags, like "v1.2" or "1.2.3.4", and are ignored.
+	CheckNearVersion Check = "near-version"
+)
+
+// Finding is a problem Lint found with the tags of a repository.
+type Finding struct {
+	Check    Check
+	Severity Severity
+
+	// Tags are the names of the tags involved.
+	Tags []string
+
+	// Message describes the problem, and Fix how to solve it.
+	Message string
+	Fix     string
+}
+
+// nearVersionRegExp matches the last path segment of tag names that
+// look like a version: an optional prefix of letters and dashes,
+// followed by at least two dot-separated numbers.
+var nearVersionRegExp = regexp.MustCompile(`^(?P<prefix>[A-Za-z][A-Za-z_-]*)?(?P<version>\d+(?:\.\d+)+(?:[-+.].*)?)$`)
+
+// Lint checks the tags of the repository for problems that make the
+// derived versions surprising, across all scopes, among the tags of
+// the Context's versioning scheme.  Tags are coerced like for the
+// version, so coerced tags are checked with the others and not
+// reported as near versions.  Near versions are only reported for
+// SemVer.  Findings are ordered by check, then by the name of their
+// first tag.
+func Lint(ctx context.Context, cx *Context) ([]Finding, error) {
+	commitTags, doneFn := IterCommitTags(ctx, cx)
+	scheme := cx.Scheme()
+
+	var findings []Finding
+	var versionTags []VersionTag
+	for ct := range commitTags {
+		vt, err := parseVersionTag(ct, scheme, cx.coerceTags)
+		if err != nil {
+			if scheme != SemVer {
+				continue
+			}
+			if f, ok := lintNearVersion(ct.TagName); ok {
+				findings = append(findings, f)
+			}
+			continue
+		}
+		versionTags = append(versionTags, vt)
+	}
+	if err := doneFn(); err != nil {
+		return nil, fmt.Errorf("collect tags: %w", err)
+	}
+	slices.SortStableFunc(versionTags, VersionTag.CompareDesc)
+
+	byScope := map[Scope][]VersionTag{}
+	for _, vt := range versionTags {
+		byScope[vt.VersionSpec.Scope] = append(byScope[vt.VersionSpec.Scope], vt)
+	}
+
+	graph := newCommitGraph(ctx, cx)
+	defer func() { _ = graph.Close() }()
+
+	stranded, err := lintStrandedTags(cx, graph, versionTags)
+	if err != nil {
+		return nil, err
+	}
+	findings = append(findings, stranded...)
+
+	for scope, tags := range byScope {
+		findings = append(findings, lintDuplicateVersions(tags)...)
+		findings = append(findings, lintMixedPrefixes(scope, tags)...)
+
+		nonMonotonic, err := lintNonMonotonic(graph, tags)
+		if err != nil {
+			return nil, err
+		}
+		findings = append(findings, nonMonotonic...)
+	}
+
+	order := []Check{CheckNonMonotonic, CheckDuplicateVersion, CheckStrandedTag, CheckMixedPrefixes, CheckNearVersion}
+	slices.SortStableFunc(findings, func(a, b Finding) int {
+		return cmp.Or(
+			cmp.Compare(slices.Index(order, a.Check), slices.Index(order, b.Check)),
+			cmp.Compare(a.Tags[0], b.Tags[0]),
+		)
+	})
+	return findings, nil
+}
+
+// lintDuplicateVersions reports versions with several tags in tags,
+// which belong to one scope.  Several tags on one commit only make the
+// tag name ambiguous; on different commits they make the version's
+// commit depend on tie-breakers.
+func lintDuplicateVersions(tags []VersionTag) []Finding {
+	var out []Finding
+	for i := 0; i < len(tags); {
+		// Tags of one version are adjacent, sorted highest first.
+		j := i + 1
+		for j < len(tags) && tags[j].VersionSpec.Version.Equal(&tags[i].VersionSpec.Version) {
+			j++
+		}
+		group := tags[i:j]
+		i = j
+
+		if len(group) < 2 {
+			continue
+		}
+
+		severity := SeverityWarning
+		var names, drop []string
+		for _, vt := range group {
+			names = append(names, vt.TagName)
+			if vt.CommitHash != group[0].CommitHash {
+				severity = SeverityError
+			}
+		}
+		for _, name := range names[1:] {
+			drop = append(drop, fmt.Sprintf("`git tag -d %s`", name))
+		}
+
+		out = append(out, Finding{
+			Check:    CheckDuplicateVersion,
+			Severity: severity,
+			Tags:     names,
+			Message:  fmt.Sprintf("version %s is tagged %d times: %s", group[0].VersionSpec.WithScope(RootScope()).WithPrefix("").String(), len(names), strings.Join(names, ", ")),
+			Fix:      fmt.Sprintf("keep %s, which wins the tie-break, and delete the rest: %s", names[0], strings.Join(drop, ", ")),
+		})
+	}
+	return out
+}
+
+// lintMixedPrefixes reports scope when tags, which belong to it, use
+// several prefixes.
+func lintMixedPrefixes(scope Scope, tags []VersionTag) []Finding {
+	counts := map[string]int{}
+	examples := map[string]string{}
+	for _, vt := range tags {
+		p := vt.VersionSpec.Prefix
+		if counts[p] == 0 {
+			examples[p] = vt.TagName
+		}
+		counts[p]++
+	}
+	if len(counts) < 2 {
+		return nil
+	}
+
+	prefixes := slices.SortedFunc(maps.Keys(counts), func(a, b string) int {
+		return cmp.Or(cmp.Compare(counts[b], counts[a]), cmp.Compare(a, b))
+	})
+
+	var names, parts []string
+	for _, p := range prefixes {
+		names = append(names, examples[p])
+		parts = append(parts, fmt.Sprintf("%q (%d tag(s), like %s)", p, counts[p], examples[p]))
+	}
+
+	scopeName := "the root scope"
+	if !scope.IsRoot() {
+		scopeName = fmt.Sprintf("scope %q", scope.String())
+	}
+
+	return []Finding{{
+		Check:    CheckMixedPrefixes,
+		Severity: SeverityWarning,
+		Tags:     names,
+		Message:  fmt.Sprintf("%s mixes prefixes %s", scopeName, strings.Join(parts, ", ")),
+		Fix:      fmt.Sprintf("use the prefix %q for new tags", prefixes[0]),
+	}}
+}
+
+// lintStrandedTags reports the tags whose commits no branch or
+// remote-tracking ref reaches.  Without any branch, no tag is
+// stranded, like in BuildGuide.
+func lintStrandedTags(cx *Context, graph *commitGraph, tags []VersionTag) ([]Finding, error) {
+	tips, hasTips, err := graph.walkBranchTips(cx.Repository())
+	if err != nil {
+		return nil, fmt.Errorf("walk branch tips: %w", err)
+	}
+	if !hasTips {
+		return nil, nil
+	}
+
+	var out []Finding
+	for _, vt := range tags {
+		onBranch, err := tips.contains(vt.CommitHash)
+		switch {
+		case errors.Is(err, plumbing.ErrObjectNotFound):
+			// Beyond the boundary of a shallow clone.
+			continue
+		case err != nil:
+			return nil, fmt.Errorf("check tag commit %s: %w", vt.CommitHash, err)
+		case onBranch:
+			continue
+		}
+
+		out = append(out, Finding{
+			Check:    CheckStrandedTag,
+			Severity: SeverityWarning,
+			Tags:     []string{vt.TagName},
+			Message:  fmt.Sprintf("%s is on commit %s, which no branch contains, so it is ignored", vt.TagName, vt.CommitHash),
+			Fix:      fmt.Sprintf("create a branch containing it, `git branch <name> %s`, or delete the tag, `git tag -d %s`", vt.TagName, vt.TagName),
+		})
+	}
+	return out, nil
+}
+
+// lintNonMonotonic reports the tags in tags, which belong to one scope
+// and are sorted highest first, whose commit descends from the commit
+// of a higher version.
+func lintNonMonotonic(graph *commitGraph, tags []VersionTag) ([]Finding, error) {
+	var out []Finding
+	for i, vt := range tags {
+		ancestry, err := graph.newAncestryWalker(vt.CommitHash)
+		switch {
+		case errors.Is(err, plumbing.ErrObjectNotFound):
+			continue
+		case err != nil:
+			return nil, fmt.Errorf("walk history of %s: %w", vt.TagName, err)
+		}
+
+		for _, higher := range tags[:i] {
+			if higher.CommitHash == vt.CommitHash || !higher.VersionSpec.Version.GreaterThan(&vt.VersionSpec.Version) {
+				continue
+			}
+
+			isAncestor, err := ancestry.contains(higher.CommitHash)
+			switch {
+			case errors.Is(err, plumbing.ErrObjectNotFound):
+				continue
+			case err != nil:
+				return nil, fmt.Errorf("check tag commit %s: %w", higher.CommitHash, err)
+			case !isAncestor:
+				continue
+			}
+
+			out = append(out, Finding{
+				Check:    CheckNonMonotonic,
+				Severity: SeverityError,
+				Tags:     []string{vt.TagName, higher.TagName},
+				Message:  fmt.Sprintf("%s is on a descendant of %s, so versions go backwards", vt.TagName, higher.TagName),
+				Fix:      fmt.Sprintf("delete %s, `git tag -d %s`, or tag a version higher than %s instead", vt.TagName, vt.TagName, higher.TagName),
+			})
+			break
+		}
+	}
+	return out, nil
+}
+
+// lintNearVersion reports name when it looks like a version but is
+// not a version tag, suggesting the version tag it most likely means.
+func lintNearVersion(name string) (Finding, bool) {
+	dir, base := path.Split(name)
+	m := nearVersionRegExp.FindStringSubmatch(base)
+	if m == nil {
+		return Finding{}, false
+	}
+
+	f := Finding{
+		Check:    CheckNearVersion,
+		Severity: SeverityWarning,
+		Tags:     []string{name},
+		Message:  fmt.Sprintf("%s looks like a version but is not a version tag, so it is ignored", name),
+		Fix:      fmt.Sprintf("delete it, `git tag -d %s`, or retag it with a version tag", name),
+	}
+
+	v, err := semver.NewVersion(m[nearVersionRegExp.SubexpIndex("version")])
+	if err != nil {
+		return f, true
+	}
+	for _, prefix := range []string{m[nearVersionRegExp.SubexpIndex("prefix")], "v"} {
+		suggested := dir + prefix + v.String()
+		if _, err := ParseVersionSpec(suggested); err == nil {
+			f.Fix = fmt.Sprintf("retag it as %s, `git tag -a -m %s %s %s^{} && git tag -d %s`", suggested, suggested, suggested, name, name)
+			break
+		}
+	}
+	return f, true
+}
@@ -0,0 +1,253 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package gitrepo_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0x5a17ed/semverkzeug/internal/gitfixture"
+	"github.com/0x5a17ed/semverkzeug/internal/gitrepo"
+)
+
+func TestLint(t *testing.T) {
+	_ = gitfixture.NewHomeFixture(t)
+	gitfixture.IsolateGitConfig(t)
+
+	// newHistory creates a repository with four commits on main, the
+	// oldest being main~3.
+	newHistory := func(t *testing.T) string {
+		dir := t.TempDir()
+		gitfixture.RunGit(t, dir, "init", "-q", "-b", "main")
+		for _, msg := range []string{"one", "two", "three", "four"} {
+			gitfixture.RunGit(t, dir, "commit", "-q", "--allow-empty", "-m", msg)
+		}
+		return dir
+	}
+
+	type finding struct {
+		check    gitrepo.Check
+		severity gitrepo.Severity
+		tags     []string
+	}
+
+	tests := []struct {
+		name    string
+		arrange func(t *testing.T, dir string)
+		coerce  bool
+		calver  string
+		want    []finding
+
+		// fixes are the expected fixes of some findings, by their
+		// first tag.
+		fixes map[gitrepo.Check]map[string]string
+
+		// messages are the expected messages of some findings, by
+		// their first tag.
+		messages map[gitrepo.Check]map[string]string
+	}{
+		{
+			name: "clean",
+			arrange: func(t *testing.T, dir string) {
+				gitfixture.RunGit(t, dir, "tag", "v1.0.0", "main~3")
+				gitfixture.RunGit(t, dir, "tag", "v1.1.0", "main~1")
+				gitfixture.RunGit(t, dir, "tag", "mod/1.0.0", "main")
+			},
+		},
+		{
+			name: "duplicate-on-one-commit",
+			arrange: func(t *testing.T, dir string) {
+				gitfixture.RunGit(t, dir, "tag", "-a", "-m", "v1.0.0", "v1.0.0", "main")
+				gitfixture.RunGit(t, dir, "tag", "-a", "-m", "v1.0.0+build", "v1.0.0+build", "main")
+			},
+			want: []finding{{
+				check:    gitrepo.CheckDuplicateVersion,
+				severity: gitrepo.SeverityWarning,
+				tags:     []string{"v1.0.0+build", "v1.0.0"},
+			}},
+		},
+		{
+			name: "duplicate-on-two-commits",
+			arrange: func(t *testing.T, dir string) {
+				gitfixture.RunGit(t, dir, "tag", "v1.0.0", "main~1")
+				gitfixture.RunGit(t, dir, "tag", "1.0.0", "main")
+			},
+			fixes: map[gitrepo.Check]map[string]string{
+				gitrepo.CheckMixedPrefixes: {"1.0.0": `use the prefix "" for new tags`},
+			},
+			want: []finding{
+				{
+					check:    gitrepo.CheckDuplicateVersion,
+					severity: gitrepo.SeverityError,
+					tags:     []string{"v1.0.0", "1.0.0"},
+				},
+				{
+					check:    gitrepo.CheckMixedPrefixes,
+					severity: gitrepo.SeverityWarning,
+					tags:     []string{"1.0.0", "v1.0.0"},
+				},
+			},
+		},
+		{
+			name: "stranded",
+			arrange: func(t *testing.T, dir string) {
+				gitfixture.RunGit(t, dir, "tag", "v1.0.0", "main~3")
+				gitfixture.RunGit(t, dir, "checkout", "-q", "-b", "side", "main~1")
+				gitfixture.RunGit(t, dir, "commit", "-q", "--allow-empty", "-m", "side")
+				gitfixture.RunGit(t, dir, "tag", "v2.0.0")
+				gitfixture.RunGit(t, dir, "checkout", "-q", "main")
+				gitfixture.RunGit(t, dir, "branch", "-q", "-D", "side")
+			},
+			want: []finding{{
+				check:    gitrepo.CheckStrandedTag,
+				severity: gitrepo.SeverityWarning,
+				tags:     []string{"v2.0.0"},
+			}},
+		},
+		{
+			name: "non-monotonic",
+			arrange: func(t *testing.T, dir string) {
+				gitfixture.RunGit(t, dir, "tag", "v1.0.0", "main~3")
+				gitfixture.RunGit(t, dir, "tag", "v2.0.0", "main~2")
+				gitfixture.RunGit(t, dir, "tag", "v1.5.0", "main")
+				gitfixture.RunGit(t, dir, "tag", "mod/v3.0.0", "main~1")
+			},
+			want: []finding{{
+				check:    gitrepo.CheckNonMonotonic,
+				severity: gitrepo.SeverityError,
+				tags:     []string{"v1.5.0", "v2.0.0"},
+			}},
+		},
+		{
+			name: "near-version",
+			arrange: func(t *testing.T, dir string) {
+				gitfixture.RunGit(t, dir, "tag", "v1.2", "main")
+				gitfixture.RunGit(t, dir, "tag", "mod/release-1.3.0", "main")
+				gitfixture.RunGit(t, dir, "tag", "1.2.3.4", "main")
+				gitfixture.RunGit(t, dir, "tag", "nightly", "main")
+				gitfixture.RunGit(t, dir, "tag", "build-42", "main")
+			},
+			fixes: map[gitrepo.Check]map[string]string{
+				gitrepo.CheckNearVersion: {
+					"1.2.3.4":           "delete it, `git tag -d 1.2.3.4`, or retag it with a version tag",
+					"mod/release-1.3.0": "retag it as mod/v1.3.0, `git tag -a -m mod/v1.3.0 mod/v1.3.0 mod/release-1.3.0^{} && git tag -d mod/release-1.3.0`",
+					"v1.2":              "retag it as v1.2.0, `git tag -a -m v1.2.0 v1.2.0 v1.2^{} && git tag -d v1.2`",
+				},
+			},
+			want: []finding{
+				{
+					check:    gitrepo.CheckNearVersion,
+					severity: gitrepo.SeverityWarning,
+					tags:     []string{"1.2.3.4"},
+				},
+				{
+					check:    gitrepo.CheckNearVersion,
+					severity: gitrepo.SeverityWarning,
+					tags:     []string{"mod/release-1.3.0"},
+				},
+				{
+					check:    gitrepo.CheckNearVersion,
+					severity: gitrepo.SeverityWarning,
+					tags:     []string{"v1.2"},
+				},
+			},
+		},
+		{
+			name: "broken-branch",
+			arrange: func(t *testing.T, dir string) {
+				gitfixture.RunGit(t, dir, "tag", "v1.0.0", "main")
+				gitfixture.WriteFile(t, filepath.Join(dir, ".git", "refs", "heads", "broken"), strings.Repeat("1", 40)+"\n")
+			},
+		},
+		{
+			name: "calver-duplicate",
+			arrange: func(t *testing.T, dir string) {
+				gitfixture.RunGit(t, dir, "tag", "v2026.01.3", "main~1")
+				gitfixture.RunGit(t, dir, "tag", "app/v2026.01.3", "main~1")
+				gitfixture.RunGit(t, dir, "tag", "2026.01.3", "main~1")
+			},
+			calver: "YYYY.0M.MICRO",
+			messages: map[gitrepo.Check]map[string]string{
+				gitrepo.CheckDuplicateVersion: {"v2026.01.3": "version 2026.01.3 is tagged 2 times: v2026.01.3, 2026.01.3"},
+			},
+			want: []finding{
+				{
+					check:    gitrepo.CheckDuplicateVersion,
+					severity: gitrepo.SeverityWarning,
+					tags:     []string{"v2026.01.3", "2026.01.3"},
+				},
+				{
+					check:    gitrepo.CheckMixedPrefixes,
+					severity: gitrepo.SeverityWarning,
+					tags:     []string{"2026.01.3", "v2026.01.3"},
+				},
+			},
+		},
+		{
+			name: "coerced",
+			arrange: func(t *testing.T, dir string) {
+				gitfixture.RunGit(t, dir, "tag", "v1.2", "main~2")
+				gitfixture.RunGit(t, dir, "tag", "v1.1.0", "main")
+			},
+			coerce: true,
+			want: []finding{{
+				check:    gitrepo.CheckNonMonotonic,
+				severity: gitrepo.SeverityError,
+				tags:     []string{"v1.1.0", "v1.2"},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			dir := newHistory(t)
+			tt.arrange(t, dir)
+
+			cx := openWorktree(t, dir)
+			cx.SetCoerceTags(tt.coerce)
+			if tt.calver != "" {
+				scheme, err := gitrepo.ParseCalVer(tt.calver)
+				require.NoError(t, err)
+				cx.SetScheme(scheme)
+			}
+
+			// Act
+			findings, err := gitrepo.Lint(t.Context(), cx)
+			require.NoError(t, err)
+
+			// Assert
+			var got []finding
+			for _, f := range findings {
+				assert.NotEmpty(t, f.Message)
+				assert.NotEmpty(t, f.Fix)
+
+				if fix, ok := tt.fixes[f.Check][f.Tags[0]]; ok {
+					assert.Equal(t, fix, f.Fix)
+				}
+				if msg, ok := tt.messages[f.Check][f.Tags[0]]; ok {
+					assert.Equal(t, msg, f.Message)
+				}
+				got = append(got, finding{check: f.Check, severity: f.Severity, tags: f.Tags})
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
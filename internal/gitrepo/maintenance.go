@@ -0,0 +1,164 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package gitrepo
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// ErrOutsideVersionLine is returned when a version doesn't belong to
+// the version line of the maintenance branch checked out.
+var ErrOutsideVersionLine = errors.New("version outside the line of the maintenance branch")
+
+// VersionLine is the versions a maintenance branch releases: the
+// patch versions of one major.minor, like "1.4.x", or the minor and
+// patch versions of one major version, like "1.x".
+type VersionLine struct {
+	Major uint64
+	Minor uint64
+
+	// AnyMinor is set for the line of a whole major version.
+	AnyMinor bool
+}
+
+var versionLineRegExp = regexp.MustCompile(`^v?(?P<major>` + numericIdentifier + `)\.(?:(?P<minor>` + numericIdentifier + `)(?:\.x)?|x)$`)
+
+// ParseVersionLine parses a version line like "1.4", "v1.4.x" or
+// "1.x".
+func ParseVersionLine(s string) (VersionLine, error) {
+	m := match(versionLineRegExp, s)
+	if m == nil {
+		return VersionLine{}, fmt.Errorf("%#q: invalid version line format", s)
+	}
+
+	var l VersionLine
+	var err error
+	if l.Major, err = strconv.ParseUint(m["major"], 10, 64); err != nil {
+		return VersionLine{}, fmt.Errorf("%#q: invalid version line format", s)
+	}
+	if m["minor"] == "" {
+		l.AnyMinor = true
+	} else if l.Minor, err = strconv.ParseUint(m["minor"], 10, 64); err != nil {
+		return VersionLine{}, fmt.Errorf("%#q: invalid version line format", s)
+	}
+	return l, nil
+}
+
+func (l VersionLine) String() string {
+	if l.AnyMinor {
+		return fmt.Sprintf("%d.x", l.Major)
+	}
+	return fmt.Sprintf("%d.%d.x", l.Major, l.Minor)
+}
+
+// Contains reports whether v belongs to the line.
+func (l VersionLine) Contains(v semver.Version) bool {
+	return v.Major() == l.Major && (l.AnyMinor || v.Minor() == l.Minor)
+}
+
+// MaintenanceBranch is a branch that releases a single version line.
+type MaintenanceBranch struct {
+	// Name is the short name of the branch, like "release/1.4".
+	Name string
+
+	// Pattern is the maintenance branch pattern Name matches.
+	Pattern string
+
+	Line VersionLine
+}
+
+// SetMaintenanceBranches sets the name patterns of maintenance
+// branches, like "release/*", where the "*" stands for the version
+// line, like "1.4" or "1.x".  Empty patterns are skipped.
+func (cx *Context) SetMaintenanceBranches(patterns []string) error {
+	var out []string
+	for _, p := range patterns {
+		if p == "" {
+			continue
+		}
+		if strings.Count(p, "*") != 1 {
+			return fmt.Errorf("%#q: maintenance branch pattern must contain a single *", p)
+		}
+		out = append(out, p)
+	}
+	cx.maintenanceBranches = out
+	return nil
+}
+
+// matchMaintenanceBranch returns the maintenance branch named name,
+// if it matches one of the patterns and names a version line.
+func (cx *Context) matchMaintenanceBranch(name string) *MaintenanceBranch {
+	for _, p := range cx.maintenanceBranches {
+		prefix, suffix, _ := strings.Cut(p, "*")
+		if len(name) <= len(prefix)+len(suffix) || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, suffix) {
+			continue
+		}
+
+		line, err := ParseVersionLine(name[len(prefix) : len(name)-len(suffix)])
+		if err != nil {
+			continue
+		}
+		return &MaintenanceBranch{Name: name, Pattern: p, Line: line}
+	}
+	return nil
+}
+
+// CurrentMaintenanceBranch returns the maintenance branch checked out
+// at HEAD.  Returns nil when HEAD is detached or on any other branch.
+func CurrentMaintenanceBranch(cx *Context) (*MaintenanceBranch, error) {
+	if len(cx.maintenanceBranches) == 0 {
+		return nil, nil
+	}
+
+	branch, ok, err := currentBranch(cx)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return cx.matchMaintenanceBranch(branch), nil
+}
+
+// CheckVersionLine returns an error wrapping ErrOutsideVersionLine
+// when ref is the commit checked out on a maintenance branch and vs is
+// outside the branch's version line.  Only semantic versions have
+// version lines.
+func CheckVersionLine(cx *Context, ref *plumbing.Reference, vs VersionSpec) error {
+	if cx.Scheme() != SemVer || ref == nil {
+		return nil
+	}
+
+	atHead, err := IsHead(cx, ref.Hash())
+	if err != nil || !atHead {
+		return err
+	}
+
+	branch, err := CurrentMaintenanceBranch(cx)
+	if err != nil || branch == nil {
+		return err
+	}
+
+	if !branch.Line.Contains(vs.Version) {
+		return fmt.Errorf("branch %s releases %s only, not %s: %w", branch.Name, branch.Line, vs.String(), ErrOutsideVersionLine)
+	}
+	return nil
+}
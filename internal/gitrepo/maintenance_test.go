@@ -0,0 +1,132 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package gitrepo_test
+
+import (
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0x5a17ed/semverkzeug/internal/gitfixture"
+	"github.com/0x5a17ed/semverkzeug/internal/gitrepo"
+)
+
+func TestParseVersionLine(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{input: "1.4", want: "1.4.x"},
+		{input: "v1.4", want: "1.4.x"},
+		{input: "1.4.x", want: "1.4.x"},
+		{input: "1.x", want: "1.x"},
+		{input: "v0.x", want: "0.x"},
+		{input: "1", wantErr: true},
+		{input: "1.4.2", wantErr: true},
+		{input: "01.4", wantErr: true},
+		{input: "next", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := gitrepo.ParseVersionLine(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.String())
+		})
+	}
+}
+
+func TestSetMaintenanceBranches(t *testing.T) {
+	cx := gitfixture.RepoEmpty(t)
+
+	assert.NoError(t, cx.SetMaintenanceBranches([]string{"release/*", "", "maint-*-lts"}))
+	assert.Error(t, cx.SetMaintenanceBranches([]string{"release"}))
+	assert.Error(t, cx.SetMaintenanceBranches([]string{"release/*/*"}))
+}
+
+func TestCheckVersionLine(t *testing.T) {
+	tests := []struct {
+		branch  string
+		version string
+		wantErr bool
+	}{
+		{branch: "release/1.4", version: "v1.4.8"},
+		{branch: "release/1.4", version: "v1.4.8-dev.1"},
+		{branch: "release/1.4", version: "v1.5.0", wantErr: true},
+		{branch: "release/1.4", version: "v2.0.0", wantErr: true},
+		{branch: "release/v1.x", version: "v1.5.0"},
+		{branch: "release/1.x", version: "v2.0.0", wantErr: true},
+		{branch: "maint-1.4-lts", version: "v1.5.0", wantErr: true},
+		{branch: "release/next", version: "v2.0.0"},
+		{branch: "feature/1.4", version: "v2.0.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.branch+" "+tt.version, func(t *testing.T) {
+			// Arrange
+			cx := gitfixture.RepoWithOneCommitNoTagsClean(t)
+			gitfixture.Checkout(t, cx, tt.branch, true)
+			require.NoError(t, cx.SetMaintenanceBranches([]string{"release/*", "maint-*-lts"}))
+
+			vs, err := gitrepo.ParseVersionSpec(tt.version)
+			require.NoError(t, err)
+
+			// Act
+			err = gitrepo.CheckVersionLine(cx, gitfixture.Head(t, cx), vs)
+
+			// Assert
+			if tt.wantErr {
+				assert.ErrorIs(t, err, gitrepo.ErrOutsideVersionLine)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	t.Run("other commit", func(t *testing.T) {
+		// Arrange: describe the parent while on the maintenance branch.
+		cx := gitfixture.RepoWithOneCommitNoTagsClean(t)
+		parent := gitfixture.Head(t, cx)
+		gitfixture.Checkout(t, cx, "release/1.4", true)
+		gitfixture.CommitFile(t, cx, "bar", "baa")
+		require.NoError(t, cx.SetMaintenanceBranches([]string{"release/*"}))
+
+		vs, err := gitrepo.ParseVersionSpec("v2.0.0")
+		require.NoError(t, err)
+
+		// Act & Assert
+		assert.NoError(t, gitrepo.CheckVersionLine(cx, parent, vs))
+		assert.ErrorIs(t, gitrepo.CheckVersionLine(cx, gitfixture.Head(t, cx), vs), gitrepo.ErrOutsideVersionLine)
+	})
+
+	t.Run("detached", func(t *testing.T) {
+		cx := gitfixture.RepoWithOneCommitNoTagsClean(t)
+		head := gitfixture.Head(t, cx)
+		require.NoError(t, gitfixture.Worktree(t, cx).Checkout(&git.CheckoutOptions{Hash: head.Hash()}))
+		require.NoError(t, cx.SetMaintenanceBranches([]string{"*"}))
+
+		vs, err := gitrepo.ParseVersionSpec("v2.0.0")
+		require.NoError(t, err)
+
+		assert.NoError(t, gitrepo.CheckVersionLine(cx, head, vs))
+	})
+}
@@ -0,0 +1,171 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package gitrepo
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-billy/v5/osfs"
+	billyutil "github.com/go-git/go-billy/v5/util"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing/cache"
+	storagevfs "github.com/go-git/go-git/v5/storage/filesystem"
+	"github.com/go-git/go-git/v5/storage/filesystem/dotgit"
+)
+
+// tolerableExtensions are the repository extensions go-git refuses
+// even though they don't change how it reads a repository: the
+// worktree config is applied by loadEffectiveConfig, and objects
+// missing from a partial clone are fetched where it matters.  git
+// names them case-insensitively.
+var tolerableExtensions = []string{"worktreeconfig", "partialclone", "preciousobjects"}
+
+// openRepository opens the repository containing p.  Repositories
+// using only tolerableExtensions are opened as well, notably those
+// made by `git clone --sparse` or `--filter`.
+func openRepository(p string) (*git.Repository, error) {
+	repo, err := git.PlainOpenWithOptions(p, &git.PlainOpenOptions{
+		DetectDotGit:          true,
+		EnableDotGitCommonDir: true,
+	})
+	if !errors.Is(err, git.ErrUnknownExtension) && !errors.Is(err, git.ErrUnsupportedExtensionRepositoryFormatVersion) {
+		return repo, err
+	}
+
+	dot, wt, findErr := findDotGit(p)
+	if findErr != nil {
+		return nil, err
+	}
+
+	st := storagevfs.NewStorage(dot, cache.NewObjectLRUDefault())
+	repo, err = git.Open(&extensionTolerantStorage{st}, wt)
+	if err != nil {
+		return nil, err
+	}
+
+	// The extensions only had to be hidden from the check in Open.
+	repo.Storer = st
+	return repo, nil
+}
+
+// extensionTolerantStorage hides tolerableExtensions from go-git.
+type extensionTolerantStorage struct {
+	*storagevfs.Storage
+}
+
+func (s *extensionTolerantStorage) Config() (*config.Config, error) {
+	cfg, err := s.Storage.Config()
+	if err != nil || !cfg.Raw.HasSection("extensions") {
+		return cfg, err
+	}
+
+	section := cfg.Raw.Section("extensions")
+	for _, name := range tolerableExtensions {
+		section.RemoveOption(name)
+	}
+	if len(section.Options) == 0 {
+		cfg.Raw.RemoveSection("extensions")
+	}
+
+	return cfg, nil
+}
+
+// findDotGit locates the repository containing p the way
+// git.PlainOpenWithOptions does: the closest .git directory, or .git
+// file pointing to one, in p or its parents, combined with the common
+// directory of linked worktrees.
+func findDotGit(p string) (dot, wt billy.Filesystem, err error) {
+	p, err = filepath.Abs(p)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for {
+		fi, err := os.Stat(filepath.Join(p, git.GitDirName))
+		if err == nil {
+			wt = osfs.New(p)
+			if fi.IsDir() {
+				dot = osfs.New(filepath.Join(p, git.GitDirName))
+			} else if dot, err = readDotGitFile(wt); err != nil {
+				return nil, nil, err
+			}
+			break
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, nil, err
+		}
+
+		parent := filepath.Dir(p)
+		if parent == p {
+			return nil, nil, git.ErrRepositoryNotExists
+		}
+		p = parent
+	}
+
+	common, err := readCommonDir(dot)
+	switch {
+	case err != nil:
+		return nil, nil, err
+	case common == "":
+		return dot, wt, nil
+	}
+	return dotgit.NewRepositoryFilesystem(dot, osfs.New(common)), wt, nil
+}
+
+// readCommonDir returns the absolute path of the common directory the
+// git directory of a linked worktree points to, or "" when dot is not
+// the git directory of a linked worktree.
+func readCommonDir(dot billy.Filesystem) (string, error) {
+	commonDir, err := billyutil.ReadFile(dot, "commondir")
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return "", nil
+	case err != nil:
+		return "", err
+	}
+
+	common := strings.TrimSpace(string(commonDir))
+	if !filepath.IsAbs(common) {
+		common = filepath.Join(dot.Root(), common)
+	}
+	return filepath.Clean(common), nil
+}
+
+// readDotGitFile follows a "gitdir: <path>" .git file.
+func readDotGitFile(wt billy.Filesystem) (billy.Filesystem, error) {
+	b, err := billyutil.ReadFile(wt, git.GitDirName)
+	if err != nil {
+		return nil, err
+	}
+
+	gitdir, ok := strings.CutPrefix(string(b), "gitdir: ")
+	if !ok {
+		return nil, fmt.Errorf(".git file has no gitdir: prefix")
+	}
+
+	gitdir = strings.TrimSpace(strings.SplitN(gitdir, "\n", 2)[0])
+	if !filepath.IsAbs(gitdir) {
+		gitdir = filepath.Join(wt.Root(), gitdir)
+	}
+	return osfs.New(gitdir), nil
+}
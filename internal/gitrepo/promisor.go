@@ -0,0 +1,110 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package gitrepo
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// maxTagChainLength bounds how many tag objects are followed when
+// looking for a missing object.
+const maxTagChainLength = 16
+
+// missingTagObject returns the first object on the way from the tag
+// ref to a commit that is not in the object database.  Objects of a
+// partial clone are missing until fetched from the promisor remote.
+func missingTagObject(repo *git.Repository, ref *plumbing.Reference) (plumbing.Hash, bool) {
+	h := ref.Hash()
+	for range maxTagChainLength {
+		if err := repo.Storer.HasEncodedObject(h); err != nil {
+			return h, true
+		}
+
+		tagObj, err := repo.TagObject(h)
+		if err != nil {
+			return plumbing.ZeroHash, false
+		}
+		h = tagObj.Target
+	}
+
+	return plumbing.ZeroHash, false
+}
+
+// resolvePromisedTags resolves the tags whose objects were missing
+// after fetching those objects from the promisor remote.  It returns
+// the tags that now resolve, and the names of those that don't.
+func resolvePromisedTags(ctx context.Context, cx *Context, refs []*plumbing.Reference, missing []plumbing.Hash) ([]CommitTag, []string) {
+	unresolvedAll := func() []string {
+		names := make([]string, 0, len(refs))
+		for _, ref := range refs {
+			names = append(names, ref.Name().Short())
+		}
+		return names
+	}
+
+	cfg, err := loadEffectiveConfig(cx)
+	if err != nil || cfg.promisorRemote == "" {
+		return nil, unresolvedAll()
+	}
+
+	if err := fetchPromisorObjects(ctx, cx, cfg.promisorRemote, missing); err != nil {
+		slog.DebugContext(ctx, "fetch promised tag objects", "remote", cfg.promisorRemote, "error", err)
+		return nil, unresolvedAll()
+	}
+
+	r := cx.Repository()
+
+	var out []CommitTag
+	var unresolved []string
+	for _, ref := range refs {
+		tag, err := resolveCommitTag(r, ref)
+		switch {
+		case err != nil:
+			unresolved = append(unresolved, ref.Name().Short())
+		case tag != nil:
+			out = append(out, *tag)
+		default:
+			if _, ok := missingTagObject(r, ref); ok {
+				unresolved = append(unresolved, ref.Name().Short())
+			}
+		}
+	}
+
+	return out, unresolved
+}
+
+// fetchPromisorObjects fetches the given objects from the promisor
+// remote with native git, exactly like git fetches the objects a
+// command finds missing in a partial clone.
+func fetchPromisorObjects(ctx context.Context, cx *Context, remote string, hashes []plumbing.Hash) error {
+	var stdin strings.Builder
+	for _, h := range hashes {
+		stdin.WriteString(h.String() + "\n")
+	}
+
+	return runGitFetch(ctx, cx, strings.NewReader(stdin.String()),
+		"-c", "fetch.negotiationAlgorithm=noop",
+		"fetch", remote,
+		"--no-tags", "--no-write-fetch-head", "--recurse-submodules=no",
+		"--filter=blob:none", "--stdin",
+	)
+}
@@ -0,0 +1,173 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package gitrepo_test
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0x5a17ed/semverkzeug/internal/gitfixture"
+	"github.com/0x5a17ed/semverkzeug/internal/gitrepo"
+)
+
+// newPromisorRemote creates a repository to partially clone from:
+// v0.1.0 on main, and v0.2.0 on a side branch.  Returns its path and
+// the tag object ids by name.
+func newPromisorRemote(t *testing.T) (string, map[string]string) {
+	t.Helper()
+
+	src := t.TempDir()
+	gitfixture.RunGit(t, src, "init", "-q", "-b", "main")
+	gitfixture.RunGit(t, src, "config", "uploadpack.allowFilter", "true")
+	gitfixture.RunGit(t, src, "config", "uploadpack.allowAnySHA1InWant", "true")
+
+	gitfixture.WriteFile(t, filepath.Join(src, "foo"), "baa")
+	gitfixture.WriteFile(t, filepath.Join(src, "sub", "deep", "bar"), "baa")
+	gitfixture.RunGit(t, src, "add", ".")
+	gitfixture.RunGit(t, src, "commit", "-q", "-m", "initial")
+	gitfixture.RunGit(t, src, "tag", "-a", "-m", "v0.1.0", "v0.1.0")
+
+	gitfixture.RunGit(t, src, "checkout", "-q", "-b", "side")
+	gitfixture.WriteFile(t, filepath.Join(src, "side"), "baa")
+	gitfixture.RunGit(t, src, "add", ".")
+	gitfixture.RunGit(t, src, "commit", "-q", "-m", "side")
+	gitfixture.RunGit(t, src, "tag", "-a", "-m", "v0.2.0", "v0.2.0")
+	gitfixture.RunGit(t, src, "checkout", "-q", "main")
+
+	tags := map[string]string{}
+	for _, name := range []string{"v0.1.0", "v0.2.0"} {
+		tags[name] = gitfixture.RunGit(t, src, "rev-parse", "refs/tags/"+name)
+	}
+	return src, tags
+}
+
+// newPartialClone clones main of src without any tags, then adds refs
+// for tags, whose objects may only exist on the promisor remote.
+func newPartialClone(t *testing.T, src string, tags map[string]string, args ...string) (*gitrepo.Context, string) {
+	t.Helper()
+
+	dst := filepath.Join(t.TempDir(), "clone")
+	cloneArgs := append([]string{"clone", "-q", "--no-tags", "--single-branch"}, args...)
+	gitfixture.RunGit(t, filepath.Dir(dst), append(cloneArgs, "file://"+src, dst)...)
+
+	for name, oid := range tags {
+		gitfixture.WriteFile(t, filepath.Join(dst, ".git", "refs", "tags", name), oid+"\n")
+	}
+
+	cx, err := gitrepo.NewContextFromPath(dst)
+	require.NoError(t, err)
+	return cx, dst
+}
+
+func collectTagNames(t *testing.T, cx *gitrepo.Context) []string {
+	t.Helper()
+
+	tags, doneFn := gitrepo.IterCommitTags(t.Context(), cx)
+
+	var names []string
+	for tag := range tags {
+		names = append(names, tag.TagName)
+	}
+	require.NoError(t, doneFn())
+
+	slices.Sort(names)
+	return names
+}
+
+func TestIterCommitTags_PartialClone(t *testing.T) {
+	_ = gitfixture.NewHomeFixture(t)
+	gitfixture.IsolateGitConfig(t)
+
+	t.Run("fetches-missing-tag-objects", func(t *testing.T) {
+		// Arrange
+		src, tags := newPromisorRemote(t)
+		cx, dst := newPartialClone(t, src, tags, "--filter=blob:none")
+
+		// Act
+		got := collectTagNames(t, cx)
+
+		// Assert: Both tags resolve, and the complete list is cached.
+		assert.Equal(t, []string{"v0.1.0", "v0.2.0"}, got)
+		assert.FileExists(t, filepath.Join(dst, ".git", "semverkzeug", "cache.json"))
+	})
+
+	t.Run("promisor-unreachable", func(t *testing.T) {
+		// Arrange
+		src, tags := newPromisorRemote(t)
+		cx, _ := newPartialClone(t, src, tags, "--filter=blob:none")
+		moved := src + ".moved"
+		require.NoError(t, os.Rename(src, moved))
+
+		// Act
+		got := collectTagNames(t, cx)
+
+		// Assert: The clone came with the objects of v0.1.0.  v0.2.0
+		// can't be resolved and is skipped rather than failing.
+		assert.Equal(t, []string{"v0.1.0"}, got)
+
+		// Act: The remote is back, but the refs are unchanged.
+		require.NoError(t, os.Rename(moved, src))
+		got = collectTagNames(t, cx)
+
+		// Assert: v0.2.0 is not fetched again right away.
+		assert.Equal(t, []string{"v0.1.0"}, got)
+
+		// Act
+		gitrepo.SetPromisorRetryInterval(t, 0)
+		got = collectTagNames(t, cx)
+
+		// Assert: Once due, it is fetched again.
+		assert.Equal(t, []string{"v0.1.0", "v0.2.0"}, got)
+	})
+
+	t.Run("guide", func(t *testing.T) {
+		// Arrange
+		src, tags := newPromisorRemote(t)
+		cx, _ := newPartialClone(t, src, tags, "--filter=blob:none")
+
+		// Act
+		guide, err := gitrepo.BuildGuide(t.Context(), cx, gitfixture.Head(t, cx), gitrepo.RootScope())
+		require.NoError(t, err)
+
+		// Assert: v0.2.0 only exists on a branch that wasn't cloned.
+		require.NotNil(t, guide.HighestVersion())
+		assert.Equal(t, "v0.1.0", guide.HighestVersion().TagName)
+		assert.Zero(t, guide.Depth)
+	})
+}
+
+func TestStatus_SparseTreelessClone(t *testing.T) {
+	_ = gitfixture.NewHomeFixture(t)
+	gitfixture.IsolateGitConfig(t)
+
+	// Arrange: The trees below sub/ are never fetched.
+	src, _ := newPromisorRemote(t)
+	cx, dst := newPartialClone(t, src, nil, "--filter=tree:0", "--sparse")
+	require.NoDirExists(t, filepath.Join(dst, "sub"))
+
+	// Act
+	st, err := gitrepo.BuildWorktreeStatus(t.Context(), cx)
+	require.NoError(t, err)
+
+	// Assert
+	assert.True(t, st.IsClean(), "status:\n%s", st)
+}
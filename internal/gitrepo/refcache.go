@@ -0,0 +1,335 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package gitrepo
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/google/renameio/v2"
+)
+
+// refCacheRelPath is the path (relative to the common gitdir) of the
+// cache holding peeled tags and guide results between invocations.
+// Like the refs it depends on, it is shared by all worktrees.
+const refCacheRelPath = "semverkzeug/cache.json"
+
+// refCacheFormat is bumped whenever the cache layout changes, which
+// invalidates every cache written by older versions.
+const refCacheFormat = 2
+
+// promisorRetryInterval is how long tags whose objects could not be
+// fetched from the promisor remote are skipped without fetching them
+// again, as long as the refs are unchanged.
+var promisorRetryInterval = time.Hour
+
+// maxCachedGuides bounds the number of guide results kept for one
+// ref state.  The cache is reset once the bound is reached.
+const maxCachedGuides = 64
+
+// refCache is the on-disk record of work that only depends on the
+// refs of the repository.  Key identifies the ref state (packed-refs
+// plus every loose ref) the record was computed from; a cache with a
+// different key is stale and ignored.
+//
+// Unresolved names the tags missing from Tags because their objects
+// could not be fetched from the promisor remote at UnresolvedAt.
+type refCache struct {
+	Format       int                    `json:"format"`
+	Key          string                 `json:"key"`
+	Tags         []cachedTag            `json:"tags,omitempty"`
+	Unresolved   []string               `json:"unresolved,omitempty"`
+	UnresolvedAt time.Time              `json:"unresolved_at,omitzero"`
+	Guides       map[string]cachedGuide `json:"guides,omitempty"`
+}
+
+type cachedTag struct {
+	Name      string    `json:"name"`
+	Annotated bool      `json:"annotated,omitempty"`
+	Commit    string    `json:"commit"`
+	Date      time.Time `json:"date"`
+}
+
+type cachedGuide struct {
+	Tags      []string `json:"tags,omitempty"`
+	MergeBase string   `json:"merge_base,omitempty"`
+	Depth     int      `json:"depth"`
+}
+
+// refCacheFile is a loaded cache together with where to save it.
+type refCacheFile struct {
+	path string
+	data refCache
+}
+
+// loadRefCache reads the cache and validates it against the current
+// ref state.  Returns nil when the repository is not backed by an OS
+// filesystem or the ref state cannot be read, in which case caching
+// is skipped.  A missing, corrupt or stale cache file yields an empty
+// cache for the current ref state.
+func loadRefCache(cx *Context) *refCacheFile {
+	dotGitPath, ok := cx.CommonDotGitPath()
+	if !ok {
+		return nil
+	}
+
+	key, err := computeRefStateKey(dotGitPath)
+	if err != nil {
+		return nil
+	}
+
+	c := &refCacheFile{
+		path: filepath.Join(dotGitPath, refCacheRelPath),
+		data: refCache{Format: refCacheFormat, Key: key},
+	}
+
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return c
+	}
+
+	var prev refCache
+	if err := json.Unmarshal(data, &prev); err != nil {
+		return c
+	}
+	if prev.Format == refCacheFormat && prev.Key == key {
+		c.data = prev
+	}
+
+	return c
+}
+
+// save atomically writes the cache.  Persistence is best effort, so
+// callers are free to ignore the error.
+func (c *refCacheFile) save() error {
+	data, err := json.Marshal(&c.data)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return err
+	}
+
+	return renameio.WriteFile(c.path, data, 0o644)
+}
+
+// computeRefStateKey hashes packed-refs, the shallow boundary and
+// every loose ref below refs/.  Any ref update, including creating or
+// deleting a tag or moving a branch, changes the key.
+func computeRefStateKey(dotGitPath string) (string, error) {
+	h := sha256.New()
+
+	packed, err := os.ReadFile(filepath.Join(dotGitPath, "packed-refs"))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return "", err
+	}
+	h.Write(packed)
+	h.Write([]byte{0})
+
+	// Deepening a shallow clone changes what is reachable without
+	// necessarily touching any ref.
+	shallow, err := os.ReadFile(filepath.Join(dotGitPath, "shallow"))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return "", err
+	}
+	h.Write(shallow)
+	h.Write([]byte{0})
+
+	refsDir := filepath.Join(dotGitPath, "refs")
+	err = filepath.WalkDir(refsDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		content, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(refsDir, p)
+		if err != nil {
+			return err
+		}
+
+		h.Write([]byte(filepath.ToSlash(rel)))
+		h.Write([]byte{0})
+		h.Write(content)
+		h.Write([]byte{0})
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// commitTags returns the cached tags, or false if they were not
+// cached for the current ref state or unresolved tags are due to be
+// fetched again.
+func (c *refCacheFile) commitTags() ([]CommitTag, bool) {
+	if c == nil || c.data.Tags == nil {
+		return nil, false
+	}
+	if len(c.data.Unresolved) > 0 && time.Since(c.data.UnresolvedAt) >= promisorRetryInterval {
+		return nil, false
+	}
+
+	out := make([]CommitTag, 0, len(c.data.Tags))
+	for _, t := range c.data.Tags {
+		h, ok := parseCachedHash(t.Commit)
+		if !ok {
+			return nil, false
+		}
+		out = append(out, CommitTag{
+			TagName:     t.Name,
+			IsAnnotated: t.Annotated,
+			CommitHash:  h,
+			TagDate:     t.Date,
+		})
+	}
+	return out, true
+}
+
+// putCommitTags records tags, lacking the tags named by unresolved.
+func (c *refCacheFile) putCommitTags(tags []CommitTag, unresolved []string) {
+	// Guides built while tags were unresolved may be missing some.
+	if len(c.data.Unresolved) > 0 {
+		c.data.Guides = nil
+	}
+
+	c.data.Unresolved = unresolved
+	c.data.UnresolvedAt = time.Time{}
+	if len(unresolved) > 0 {
+		c.data.UnresolvedAt = time.Now()
+	}
+
+	c.data.Tags = make([]cachedTag, 0, len(tags))
+	for _, t := range tags {
+		c.data.Tags = append(c.data.Tags, cachedTag{
+			Name:      t.TagName,
+			Annotated: t.IsAnnotated,
+			Commit:    t.CommitHash.String(),
+			Date:      t.TagDate,
+		})
+	}
+}
+
+func parseCachedHash(s string) (plumbing.Hash, bool) {
+	if !plumbing.IsHash(s) {
+		return plumbing.ZeroHash, false
+	}
+	return plumbing.NewHash(s), true
+}
+
+// guideCacheKey identifies the guide of h within scope.  Coercing tags
+// and the versioning scheme change which tags count, so they are part
+// of the key.
+func guideCacheKey(h plumbing.Hash, scope Scope, coerceTags bool, scheme Scheme) string {
+	key := h.String() + ":" + scope.String()
+	if coerceTags {
+		key += ":coerce"
+	}
+	if scheme != SemVer {
+		key += ":" + scheme.String()
+	}
+	return key
+}
+
+// guide restores the cached guide for h within scope, with the tag
+// settings of cx.  Returns nil when there is none, or when it
+// references tags or commits that no longer resolve.
+func (c *refCacheFile) guide(cx *Context, scope Scope, h plumbing.Hash) *Guide {
+	if c == nil {
+		return nil
+	}
+
+	scheme := cx.Scheme()
+	cg, ok := c.data.Guides[guideCacheKey(h, scope, cx.coerceTags, scheme)]
+	if !ok {
+		return nil
+	}
+
+	tags, ok := c.commitTags()
+	if !ok {
+		return nil
+	}
+
+	r := cx.Repository()
+	commit, err := r.CommitObject(h)
+	if err != nil {
+		return nil
+	}
+
+	shallow, _ := r.Storer.Shallow()
+	guide := &Guide{
+		Scope:   scope,
+		Scheme:  scheme,
+		Commit:  commit,
+		Depth:   cg.Depth,
+		Shallow: len(shallow) > 0,
+	}
+
+	for _, name := range cg.Tags {
+		i := slices.IndexFunc(tags, func(ct CommitTag) bool { return ct.TagName == name })
+		if i < 0 {
+			return nil
+		}
+		vt, err := parseVersionTag(tags[i], scheme, cx.coerceTags)
+		if err != nil {
+			return nil
+		}
+		guide.Tags = append(guide.Tags, vt)
+	}
+
+	if cg.MergeBase != "" {
+		mh, ok := parseCachedHash(cg.MergeBase)
+		if !ok {
+			return nil
+		}
+		if guide.MergeBase, err = r.CommitObject(mh); err != nil {
+			return nil
+		}
+	}
+
+	return guide
+}
+
+func (c *refCacheFile) putGuide(b *GuideBuilder, g *Guide) {
+	if c.data.Guides == nil || len(c.data.Guides) >= maxCachedGuides {
+		c.data.Guides = map[string]cachedGuide{}
+	}
+
+	cg := cachedGuide{Depth: g.Depth}
+	for _, vt := range g.Tags {
+		cg.Tags = append(cg.Tags, vt.TagName)
+	}
+	if g.MergeBase != nil {
+		cg.MergeBase = g.MergeBase.Hash.String()
+	}
+
+	c.data.Guides[guideCacheKey(g.Commit.Hash, b.scope, b.coerceTags, b.scheme)] = cg
+}
@@ -0,0 +1,155 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package gitrepo_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0x5a17ed/semverkzeug/internal/gitfixture"
+	"github.com/0x5a17ed/semverkzeug/internal/gitrepo"
+)
+
+func refCachePath(t *testing.T, cx *gitrepo.Context) string {
+	t.Helper()
+
+	dotGitPath, ok := cx.DotGitPath()
+	require.True(t, ok)
+
+	return filepath.Join(dotGitPath, "semverkzeug", "cache.json")
+}
+
+func buildHeadGuide(t *testing.T, cx *gitrepo.Context) *gitrepo.Guide {
+	t.Helper()
+
+	guide, err := gitrepo.BuildGuide(t.Context(), cx, gitfixture.Head(t, cx), gitrepo.RootScope())
+	require.NoError(t, err)
+
+	return guide
+}
+
+func TestBuildGuide_RefCache(t *testing.T) {
+	t.Run("cached guide matches computed guide", func(t *testing.T) {
+		cx := gitfixture.RepoWithTwoCommitsOneTagClean(t)
+
+		first := buildHeadGuide(t, cx)
+
+		var cache struct {
+			Key    string            `json:"key"`
+			Tags   []json.RawMessage `json:"tags"`
+			Guides map[string]any    `json:"guides"`
+		}
+		data, err := os.ReadFile(refCachePath(t, cx))
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(data, &cache))
+		assert.NotEmpty(t, cache.Key)
+		assert.Len(t, cache.Tags, 1)
+		assert.Len(t, cache.Guides, 1)
+
+		second := buildHeadGuide(t, cx)
+
+		assert.Equal(t, first.String(), second.String())
+		require.Len(t, second.Tags, len(first.Tags))
+		for i := range first.Tags {
+			assert.Equal(t, first.Tags[i].VersionSpec, second.Tags[i].VersionSpec)
+			assert.Equal(t, first.Tags[i].CommitHash, second.Tags[i].CommitHash)
+			assert.True(t, first.Tags[i].TagDate.Equal(second.Tags[i].TagDate))
+		}
+	})
+
+	t.Run("cached guide is served without collecting tags", func(t *testing.T) {
+		cx := gitfixture.RepoWithTwoCommitsOneTagClean(t)
+		_ = buildHeadGuide(t, cx)
+
+		var logs bytes.Buffer
+		prev := slog.Default()
+		slog.SetDefault(slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug})))
+		t.Cleanup(func() { slog.SetDefault(prev) })
+
+		_ = buildHeadGuide(t, cx)
+
+		assert.Contains(t, logs.String(), "reuse cached guide")
+		assert.NotContains(t, logs.String(), "collect version tags")
+	})
+
+	t.Run("new tag invalidates cache", func(t *testing.T) {
+		cx := gitfixture.RepoWithTwoCommitsOneTagClean(t)
+
+		before := buildHeadGuide(t, cx)
+		require.Equal(t, 1, before.Depth)
+
+		gitfixture.CreateTag(t, cx, "v0.2.0")
+
+		after := buildHeadGuide(t, cx)
+		require.NotNil(t, after.HighestVersion())
+		assert.Equal(t, "v0.2.0", after.HighestVersion().TagName)
+		assert.Equal(t, 0, after.Depth)
+	})
+
+	t.Run("new commit is not served from cache", func(t *testing.T) {
+		cx := gitfixture.RepoWithTwoCommitsOneTagClean(t)
+
+		require.Equal(t, 1, buildHeadGuide(t, cx).Depth)
+
+		gitfixture.CommitFile(t, cx, "baz", "baa")
+
+		assert.Equal(t, 2, buildHeadGuide(t, cx).Depth)
+	})
+
+	t.Run("corrupt cache is ignored and replaced", func(t *testing.T) {
+		cx := gitfixture.RepoWithTwoCommitsOneTagClean(t)
+
+		want := buildHeadGuide(t, cx)
+
+		require.NoError(t, os.WriteFile(refCachePath(t, cx), []byte("{not json"), 0o644))
+
+		got := buildHeadGuide(t, cx)
+		assert.Equal(t, want.String(), got.String())
+
+		data, err := os.ReadFile(refCachePath(t, cx))
+		require.NoError(t, err)
+		assert.True(t, json.Valid(data))
+	})
+
+	t.Run("cache referencing missing objects is ignored", func(t *testing.T) {
+		cx := gitfixture.RepoWithTwoCommitsOneTagClean(t)
+
+		want := buildHeadGuide(t, cx)
+
+		// Point the cached merge base at a commit that does not exist.
+		var cache map[string]any
+		data, err := os.ReadFile(refCachePath(t, cx))
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(data, &cache))
+		for _, g := range cache["guides"].(map[string]any) {
+			g.(map[string]any)["merge_base"] = "0123456789012345678901234567890123456789"
+		}
+		data, err = json.Marshal(cache)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(refCachePath(t, cx), data, 0o644))
+
+		got := buildHeadGuide(t, cx)
+		assert.Equal(t, want.String(), got.String())
+	})
+}
@@ -0,0 +1,67 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package gitrepo
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// ResolveRef resolves rev, any revision understood by
+// [git.Repository.ResolveRevision] (branch, remote-tracking branch,
+// tag, hash, "HEAD~2", ...), to a reference pointing at a commit.
+//
+// When rev names a reference, the returned reference carries its
+// full name (e.g. "refs/remotes/origin/release-1.4"); otherwise the
+// name is rev itself.
+func ResolveRef(cx *Context, rev string) (*plumbing.Reference, error) {
+	r := cx.Repository()
+
+	h, err := r.ResolveRevision(plumbing.Revision(rev))
+	if err != nil {
+		return nil, fmt.Errorf("resolve revision %#q: %w", rev, err)
+	}
+
+	name := plumbing.ReferenceName(rev)
+	for _, rule := range plumbing.RefRevParseRules {
+		candidate := plumbing.ReferenceName(fmt.Sprintf(rule, rev))
+		if _, err := r.Reference(candidate, true); err == nil {
+			name = candidate
+			break
+		}
+	}
+
+	return plumbing.NewHashReference(name, *h), nil
+}
+
+// IsHead reports whether h is the commit currently checked out.  The
+// zero hash stands for the unborn HEAD of an empty repository.
+// Operations on any other commit must not consult the worktree,
+// which has nothing to do with them.
+func IsHead(cx *Context, h plumbing.Hash) (bool, error) {
+	head, err := cx.Repository().Head()
+	switch {
+	case errors.Is(err, plumbing.ErrReferenceNotFound):
+		return h.IsZero(), nil
+	case err != nil:
+		return false, fmt.Errorf("resolve HEAD: %w", err)
+	}
+
+	return h == head.Hash(), nil
+}
@@ -0,0 +1,85 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package gitrepo_test
+
+import (
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0x5a17ed/semverkzeug/internal/gitfixture"
+	"github.com/0x5a17ed/semverkzeug/internal/gitrepo"
+)
+
+func TestResolveRef(t *testing.T) {
+	cx := gitfixture.RepoWithOneCommitOneTagClean(t)
+	first := gitfixture.Head(t, cx).Hash()
+
+	gitfixture.Checkout(t, cx, "release-1.4", true)
+	gitfixture.CommitFile(t, cx, "bar", "baa")
+	branchTip := gitfixture.Head(t, cx).Hash()
+	gitfixture.Checkout(t, cx, "main", false)
+
+	require.NoError(t, cx.Repository().Storer.SetReference(plumbing.NewHashReference(
+		plumbing.NewRemoteReferenceName("origin", "release-1.4"), branchTip,
+	)))
+
+	tests := []struct {
+		rev      string
+		wantName plumbing.ReferenceName
+		wantHash plumbing.Hash
+	}{
+		{"main", plumbing.NewBranchReferenceName("main"), first},
+		{"release-1.4", plumbing.NewBranchReferenceName("release-1.4"), branchTip},
+		{"origin/release-1.4", plumbing.NewRemoteReferenceName("origin", "release-1.4"), branchTip},
+		{"v0.1.0", plumbing.NewTagReferenceName("v0.1.0"), first},
+		{"release-1.4~1", "release-1.4~1", first},
+		{branchTip.String(), plumbing.ReferenceName(branchTip.String()), branchTip},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rev, func(t *testing.T) {
+			ref, err := gitrepo.ResolveRef(cx, tt.rev)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantName, ref.Name())
+			assert.Equal(t, tt.wantHash, ref.Hash())
+		})
+	}
+
+	t.Run("unknown", func(t *testing.T) {
+		_, err := gitrepo.ResolveRef(cx, "nope")
+		assert.ErrorIs(t, err, plumbing.ErrReferenceNotFound)
+	})
+}
+
+func TestIsHead(t *testing.T) {
+	cx := gitfixture.RepoWithTwoCommitsOneTagClean(t)
+
+	head, err := gitrepo.ResolveRef(cx, "main")
+	require.NoError(t, err)
+	ok, err := gitrepo.IsHead(cx, head.Hash())
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	tag, err := gitrepo.ResolveRef(cx, "v0.1.0")
+	require.NoError(t, err)
+	ok, err = gitrepo.IsHead(cx, tag.Hash())
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
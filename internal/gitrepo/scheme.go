@@ -0,0 +1,248 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package gitrepo
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// Scheme is a versioning scheme: how versions are written in tag
+// names and which version follows another.  Every scheme holds its
+// versions as semver.Version, so tags of any scheme are ordered,
+// scoped and given dev labels the same way.
+type Scheme interface {
+	fmt.Stringer
+
+	// ParseVersionSpec parses a version tag of the scheme.
+	ParseVersionSpec(name string) (VersionSpec, error)
+
+	// Increment returns v with the part named part incremented by
+	// inc, for schemes whose versions have semantic parts.
+	Increment(v semver.Version, part string, inc func(semver.Version) semver.Version) (semver.Version, error)
+
+	// Advance returns the version released at now after v, for
+	// schemes whose versions follow the release date.
+	Advance(v semver.Version, now time.Time) (semver.Version, error)
+
+	// initialVersion is the version before the first release.
+	initialVersion() VersionSpec
+
+	// formatVersion formats v as it appears in tag names, without
+	// scope and prefix.
+	formatVersion(v semver.Version) string
+}
+
+// SemVer is the semantic versioning scheme, the default.
+var SemVer Scheme = semVer{}
+
+type semVer struct{}
+
+func (semVer) String() string { return "semver" }
+
+func (semVer) ParseVersionSpec(name string) (VersionSpec, error) {
+	return ParseVersionSpec(name)
+}
+
+func (semVer) Increment(v semver.Version, _ string, inc func(semver.Version) semver.Version) (semver.Version, error) {
+	return inc(v), nil
+}
+
+func (semVer) Advance(semver.Version, time.Time) (semver.Version, error) {
+	return semver.Version{}, fmt.Errorf("semantic versions don't follow the calendar; bump major, minor or patch instead")
+}
+
+func (semVer) initialVersion() VersionSpec {
+	return initialVersion
+}
+
+func (semVer) formatVersion(v semver.Version) string {
+	return v.String()
+}
+
+// CalVer is a calendar versioning scheme: versions name the year and
+// month of their release, and optionally count the releases within
+// the month, like "2026.10.3" for YYYY.0M.MICRO.  Versions are held
+// with the year as major, the month as minor and MICRO as patch
+// version; two-digit years count from 2000.  Dates are taken in UTC.
+type CalVer struct {
+	format string
+
+	year  string // "YYYY", "YY" or "0Y".
+	month string // "MM" or "0M".
+	micro bool
+}
+
+// calVerRegExp matches the version tags of every CalVer format;
+// CalVer.ParseVersionSpec checks the widths of the fields.
+var calVerRegExp = regexp.MustCompile(`^` + scopePart +
+	`(?P<prefix>v)?` +
+	`(?P<year>\d+)\.(?P<month>\d+)(?:\.(?P<micro>0|[1-9]\d*))?` +
+	preReleasePart +
+	buildPart +
+	`$`)
+
+// ParseCalVer parses a CalVer format: a year field (YYYY, YY or 0Y),
+// a month field (MM or 0M) and an optional MICRO field, separated by
+// dots.  Without MICRO, there is one release per month.
+func ParseCalVer(format string) (*CalVer, error) {
+	fields := strings.Split(format, ".")
+	if len(fields) < 2 || len(fields) > 3 {
+		return nil, fmt.Errorf("%#q: invalid calendar version format", format)
+	}
+
+	c := &CalVer{format: format, year: fields[0], month: fields[1]}
+	switch c.year {
+	case "YYYY", "YY", "0Y":
+	default:
+		return nil, fmt.Errorf("%#q: invalid year field %#q", format, c.year)
+	}
+	switch c.month {
+	case "MM", "0M":
+	default:
+		return nil, fmt.Errorf("%#q: invalid month field %#q", format, c.month)
+	}
+	if len(fields) == 3 {
+		if fields[2] != "MICRO" {
+			return nil, fmt.Errorf("%#q: invalid micro field %#q", format, fields[2])
+		}
+		c.micro = true
+	}
+	return c, nil
+}
+
+func (c *CalVer) String() string { return "calver:" + c.format }
+
+// ParseVersionSpec parses a version tag of the format, like
+// "2026.10.3" or "app/v26.10".  The fields must be as wide as the
+// format says.  MICRO is only optional in formats without it, where
+// it marks the dev versions between two releases, so it must be
+// nonzero and come with a prerelease there.
+func (c *CalVer) ParseVersionSpec(name string) (VersionSpec, error) {
+	m := match(calVerRegExp, name)
+	if m == nil {
+		return VersionSpec{}, fmt.Errorf("%#q: invalid version tag format", name)
+	}
+
+	s, err := ParseScope(m["scope"])
+	if err != nil {
+		return VersionSpec{}, fmt.Errorf("%#q: invalid scope format", name)
+	}
+
+	year, errY := strconv.ParseUint(m["year"], 10, 64)
+	month, errM := strconv.ParseUint(m["month"], 10, 64)
+	if errY != nil || errM != nil || month < 1 || month > 12 ||
+		c.formatYear(year) != m["year"] || c.formatMonth(month) != m["month"] {
+		return VersionSpec{}, fmt.Errorf("%#q: not a %s version", name, c.format)
+	}
+
+	var micro uint64
+	switch {
+	case m["micro"] != "":
+		micro, err = strconv.ParseUint(m["micro"], 10, 64)
+		if err != nil || (!c.micro && (micro == 0 || m["prerelease"] == "")) {
+			return VersionSpec{}, fmt.Errorf("%#q: not a %s version", name, c.format)
+		}
+	case c.micro:
+		return VersionSpec{}, fmt.Errorf("%#q: not a %s version", name, c.format)
+	}
+
+	return VersionSpec{
+		Scope:   s,
+		Prefix:  m["prefix"],
+		Version: *semver.New(year, month, micro, m["prerelease"], m["buildmetadata"]),
+		scheme:  c,
+	}, nil
+}
+
+func (c *CalVer) Increment(_ semver.Version, part string, _ func(semver.Version) semver.Version) (semver.Version, error) {
+	return semver.Version{}, fmt.Errorf("calendar versions have no %s part; bump calendar instead", part)
+}
+
+// Advance returns the version of the month of now: the next MICRO of
+// v when v is of the same month, MICRO zero otherwise.  A prerelease
+// of the same month is released as is.  Formats without MICRO allow a
+// single release per month, so their dev versions of the month can't
+// be released.
+func (c *CalVer) Advance(v semver.Version, now time.Time) (semver.Version, error) {
+	year, month := c.date(now)
+	today := *semver.New(year, month, 0, "", "")
+
+	switch {
+	case v.Major() > year || (v.Major() == year && v.Minor() > month):
+		return semver.Version{}, fmt.Errorf("version %s is newer than %s", c.formatVersion(v), c.formatVersion(today))
+	case v.Major() < year || v.Minor() < month:
+		return today, nil
+	case v.Prerelease() != "" && (c.micro || v.Patch() == 0):
+		return *semver.New(year, month, v.Patch(), "", ""), nil
+	case !c.micro:
+		return semver.Version{}, fmt.Errorf("version %s was already released this month, and %s has no MICRO field", c.formatVersion(v), c.format)
+	}
+	return v.IncPatch(), nil
+}
+
+func (c *CalVer) initialVersion() VersionSpec {
+	return VersionSpec{Version: *semver.New(0, 0, 0, "dev.0", ""), scheme: c}
+}
+
+// formatVersion writes MICRO even in formats without it when it's not
+// zero, which only dev versions have.
+func (c *CalVer) formatVersion(v semver.Version) string {
+	out := c.formatYear(v.Major()) + "." + c.formatMonth(v.Minor())
+	if c.micro || v.Patch() != 0 {
+		out += "." + strconv.FormatUint(v.Patch(), 10)
+	}
+	if v.Prerelease() != "" {
+		out += "-" + v.Prerelease()
+	}
+	if v.Metadata() != "" {
+		out += "+" + v.Metadata()
+	}
+	return out
+}
+
+func (c *CalVer) formatYear(year uint64) string {
+	switch c.year {
+	case "YYYY":
+		return fmt.Sprintf("%04d", year)
+	case "0Y":
+		return fmt.Sprintf("%02d", year)
+	}
+	return strconv.FormatUint(year, 10)
+}
+
+func (c *CalVer) formatMonth(month uint64) string {
+	if c.month == "0M" {
+		return fmt.Sprintf("%02d", month)
+	}
+	return strconv.FormatUint(month, 10)
+}
+
+// date returns the year and month of now as the format holds them.
+func (c *CalVer) date(now time.Time) (year, month uint64) {
+	y, m, _ := now.UTC().Date()
+	year = uint64(y)
+	if c.year != "YYYY" {
+		year -= 2000
+	}
+	return year, uint64(m)
+}
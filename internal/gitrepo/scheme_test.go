@@ -0,0 +1,167 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package gitrepo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCalVer(t *testing.T) {
+	for _, format := range []string{"YYYY.0M.MICRO", "YY.MM", "0Y.0M", "YYYY.MM.MICRO"} {
+		t.Run(format, func(t *testing.T) {
+			c, err := ParseCalVer(format)
+			require.NoError(t, err)
+			assert.Equal(t, "calver:"+format, c.String())
+		})
+	}
+
+	for _, format := range []string{"", "YYYY", "YYYY.0M.MICRO.MICRO", "MM.YYYY", "YYYY.DD", "YYYY.0M.PATCH"} {
+		t.Run("invalid "+format, func(t *testing.T) {
+			_, err := ParseCalVer(format)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestCalVer_ParseVersionSpec(t *testing.T) {
+	tests := []struct {
+		format  string
+		input   string
+		scope   string
+		prefix  string
+		version string
+		wantErr bool
+	}{
+		{format: "YYYY.0M.MICRO", input: "2026.10.3", version: "2026.10.3"},
+		{format: "YYYY.0M.MICRO", input: "2026.01.0", version: "2026.1.0"},
+		{format: "YYYY.0M.MICRO", input: "app/v2026.01.2-rc.1", scope: "app", prefix: "v", version: "2026.1.2-rc.1"},
+		{format: "YYYY.0M.MICRO", input: "2026.1.0", wantErr: true},
+		{format: "YYYY.0M.MICRO", input: "2026.13.0", wantErr: true},
+		{format: "YYYY.0M.MICRO", input: "26.01.0", wantErr: true},
+		{format: "YYYY.0M.MICRO", input: "2026.01", wantErr: true},
+		{format: "YYYY.0M.MICRO", input: "v1.2.3", wantErr: true},
+		{format: "YY.MM", input: "26.1", version: "26.1.0"},
+		{format: "YY.MM", input: "26.10.1-dev.0", version: "26.10.1-dev.0"},
+		{format: "YY.MM", input: "26.10.0", wantErr: true},
+		{format: "YY.MM", input: "26.10.1", wantErr: true},
+		{format: "YY.MM", input: "26.10.1+build", wantErr: true},
+		{format: "YY.MM", input: "26.01", wantErr: true},
+		{format: "YY.MM", input: "026.1", wantErr: true},
+		{format: "0Y.0M", input: "06.03", version: "6.3.0"},
+		{format: "0Y.0M", input: "6.03", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format+" "+tt.input, func(t *testing.T) {
+			c, err := ParseCalVer(tt.format)
+			require.NoError(t, err)
+
+			got, err := c.ParseVersionSpec(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.scope, got.Scope.String())
+			assert.Equal(t, tt.prefix, got.Prefix)
+			assert.Equal(t, tt.version, got.Version.String())
+			assert.Equal(t, tt.input, got.String())
+		})
+	}
+}
+
+func TestCalVer_Advance(t *testing.T) {
+	october := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		format  string
+		from    string
+		want    string
+		wantErr bool
+	}{
+		{format: "YYYY.0M.MICRO", from: "2026.09.4", want: "2026.10.0"},
+		{format: "YYYY.0M.MICRO", from: "2025.12.1", want: "2026.10.0"},
+		{format: "YYYY.0M.MICRO", from: "2026.10.3", want: "2026.10.4"},
+		{format: "YYYY.0M.MICRO", from: "2026.10.3-rc.1", want: "2026.10.3"},
+		{format: "YYYY.0M.MICRO", from: "2026.11.0", wantErr: true},
+		{format: "YY.MM", from: "26.9", want: "26.10"},
+		{format: "YY.MM", from: "26.10", wantErr: true},
+		{format: "YY.MM", from: "26.10-rc.1", want: "26.10"},
+		{format: "YY.MM", from: "26.10.1-dev.0", wantErr: true},
+		{format: "YY.MM", from: "27.1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format+" "+tt.from, func(t *testing.T) {
+			c, err := ParseCalVer(tt.format)
+			require.NoError(t, err)
+			from, err := c.ParseVersionSpec(tt.from)
+			require.NoError(t, err)
+
+			got, err := c.Advance(from.Version, october)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, from.WithVersion(got).String())
+		})
+	}
+
+	t.Run("initial version", func(t *testing.T) {
+		c, err := ParseCalVer("YYYY.0M.MICRO")
+		require.NoError(t, err)
+
+		got, err := c.Advance(c.initialVersion().Version, october)
+		require.NoError(t, err)
+		assert.Equal(t, "2026.10.0", c.formatVersion(got))
+	})
+}
+
+func TestCalVer_DevVersionSortsAfterRelease(t *testing.T) {
+	for _, tt := range []struct{ format, release string }{
+		{"YYYY.0M.MICRO", "2026.10.3"},
+		{"YY.MM", "26.10"},
+	} {
+		t.Run(tt.format, func(t *testing.T) {
+			c, err := ParseCalVer(tt.format)
+			require.NoError(t, err)
+			release, err := c.ParseVersionSpec(tt.release)
+			require.NoError(t, err)
+
+			// Dev versions bump the patch version like for SemVer.
+			dev, err := release.Version.IncPatch().SetPrerelease("dev.261019T12000000Z")
+			require.NoError(t, err)
+			devSpec := release.WithVersion(dev)
+
+			assert.True(t, dev.GreaterThan(&release.Version))
+
+			parsed, err := c.ParseVersionSpec(devSpec.String())
+			require.NoError(t, err, "dev version %s must round-trip", devSpec.String())
+			assert.True(t, parsed.Version.Equal(&dev))
+		})
+	}
+}
+
+func TestSemVer_Advance(t *testing.T) {
+	_, err := SemVer.Advance(*semver.MustParse("1.2.3"), time.Now())
+	assert.Error(t, err)
+}
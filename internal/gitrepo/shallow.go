@@ -0,0 +1,117 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package gitrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// ErrShallowHistory is returned when no version tag is reachable in a
+// shallow clone, and the version can't be determined reliably.
+var ErrShallowHistory = errors.New("no version tag reachable in shallow clone")
+
+// IsShallow reports whether the repository is a shallow clone.
+func IsShallow(cx *Context) (bool, error) {
+	hashes, err := cx.Repository().Storer.Shallow()
+	if err != nil {
+		return false, fmt.Errorf("read shallow commits: %w", err)
+	}
+	return len(hashes) > 0, nil
+}
+
+// DeepenOptions control how DeepenUntilTagged completes the history of
+// a shallow clone.
+type DeepenOptions struct {
+	// Remote is the remote to fetch from, "origin" when empty.
+	Remote string
+
+	// FetchTags fetches every tag of the remote first; clones made
+	// with --depth or --no-tags often have none.
+	FetchTags bool
+
+	// Deepen is the number of commits to deepen the history by in
+	// each round, until a version tag is reachable.  Zero disables
+	// deepening.
+	Deepen int
+}
+
+// DeepenUntilTagged builds the guide for ref like BuildGuide, fetching
+// tags and deepening the history of a shallow clone as opts allow,
+// until a version tag is reachable or the whole history has been
+// fetched.  The returned guide may still be unreliable when the
+// options don't permit fetching enough.
+func DeepenUntilTagged(ctx context.Context, cx *Context, ref *plumbing.Reference, scope Scope, opts DeepenOptions) (*Guide, error) {
+	remote := opts.Remote
+	if remote == "" {
+		remote = "origin"
+	}
+
+	guide, err := BuildGuide(ctx, cx, ref, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	fetchedTags := false
+	for guide.IsUnreliable() {
+		switch {
+		case opts.FetchTags && !fetchedTags:
+			fetchedTags = true
+			if err := fetchHistory(ctx, cx, remote, "--tags"); err != nil {
+				return nil, err
+			}
+
+		case opts.Deepen > 0:
+			before, err := cx.Repository().Storer.Shallow()
+			if err != nil {
+				return nil, fmt.Errorf("read shallow commits: %w", err)
+			}
+
+			if err := fetchHistory(ctx, cx, remote, "--deepen="+strconv.Itoa(opts.Deepen)); err != nil {
+				return nil, err
+			}
+
+			after, err := cx.Repository().Storer.Shallow()
+			if err != nil {
+				return nil, fmt.Errorf("read shallow commits: %w", err)
+			}
+			if slices.Equal(before, after) {
+				// The remote has nothing more to offer.
+				return guide, nil
+			}
+
+		default:
+			return guide, nil
+		}
+
+		if guide, err = BuildGuide(ctx, cx, ref, scope); err != nil {
+			return nil, err
+		}
+	}
+
+	return guide, nil
+}
+
+// fetchHistory runs `git fetch` against remote.
+func fetchHistory(ctx context.Context, cx *Context, remote string, args ...string) error {
+	return runGitFetch(ctx, cx, nil, append([]string{"fetch", "--quiet", remote}, args...)...)
+}
@@ -0,0 +1,137 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package gitrepo_test
+
+import (
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0x5a17ed/semverkzeug/internal/gitfixture"
+	"github.com/0x5a17ed/semverkzeug/internal/gitrepo"
+)
+
+// newShallowClone clones src with --depth=1 and without tags, the way
+// CI systems check out a repository.
+func newShallowClone(t *testing.T, src string) *gitrepo.Context {
+	t.Helper()
+
+	dst := filepath.Join(t.TempDir(), "clone")
+	gitfixture.RunGit(t, filepath.Dir(dst), "clone", "-q", "--depth=1", "--no-tags", "file://"+src, dst)
+
+	cx, err := gitrepo.NewContextFromPath(dst)
+	require.NoError(t, err)
+	return cx
+}
+
+// newTaggedRemote creates a repository with v1.0.0 tagged three
+// commits below main.
+func newTaggedRemote(t *testing.T) string {
+	t.Helper()
+
+	src := t.TempDir()
+	gitfixture.RunGit(t, src, "init", "-q", "-b", "main")
+	for i := range 4 {
+		gitfixture.WriteFile(t, filepath.Join(src, "foo"), strconv.Itoa(i))
+		gitfixture.RunGit(t, src, "add", ".")
+		gitfixture.RunGit(t, src, "commit", "-q", "-m", "commit "+strconv.Itoa(i))
+		if i == 0 {
+			gitfixture.RunGit(t, src, "tag", "-a", "-m", "v1.0.0", "v1.0.0")
+		}
+	}
+	return src
+}
+
+func TestBuildGuide_Shallow(t *testing.T) {
+	_ = gitfixture.NewHomeFixture(t)
+	gitfixture.IsolateGitConfig(t)
+
+	// Arrange
+	cx := newShallowClone(t, newTaggedRemote(t))
+
+	// Act
+	shallow, err := gitrepo.IsShallow(cx)
+	require.NoError(t, err)
+	guide, err := gitrepo.BuildGuide(t.Context(), cx, gitfixture.Head(t, cx), gitrepo.RootScope())
+	require.NoError(t, err)
+
+	// Assert
+	assert.True(t, shallow)
+	assert.True(t, guide.Shallow)
+	assert.True(t, guide.IsUnreliable())
+	assert.Nil(t, guide.HighestVersion())
+}
+
+func TestDeepenUntilTagged(t *testing.T) {
+	_ = gitfixture.NewHomeFixture(t)
+	gitfixture.IsolateGitConfig(t)
+
+	tests := []struct {
+		name       string
+		opts       gitrepo.DeepenOptions
+		wantTag    string
+		wantDepth  int
+		unreliable bool
+	}{
+		{name: "no-options", unreliable: true},
+		{
+			// The tag is fetched, but its commit stays stranded until
+			// the history connects to it.
+			name:       "fetch-tags-only",
+			opts:       gitrepo.DeepenOptions{FetchTags: true},
+			unreliable: true,
+		},
+		{
+			name:      "fetch-tags-and-deepen",
+			opts:      gitrepo.DeepenOptions{FetchTags: true, Deepen: 1},
+			wantTag:   "v1.0.0",
+			wantDepth: 3,
+		},
+		{
+			// The clone doesn't follow tags, so deepening ends with
+			// the complete history but still without the tag.
+			name:      "deepen-only",
+			opts:      gitrepo.DeepenOptions{Deepen: 2},
+			wantDepth: 4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			cx := newShallowClone(t, newTaggedRemote(t))
+
+			// Act
+			guide, err := gitrepo.DeepenUntilTagged(t.Context(), cx, gitfixture.Head(t, cx), gitrepo.RootScope(), tt.opts)
+			require.NoError(t, err)
+
+			// Assert
+			assert.Equal(t, tt.unreliable, guide.IsUnreliable())
+			if tt.wantTag == "" {
+				assert.Nil(t, guide.HighestVersion())
+			} else {
+				require.NotNil(t, guide.HighestVersion())
+				assert.Equal(t, tt.wantTag, guide.HighestVersion().TagName)
+			}
+			if !tt.unreliable {
+				assert.Equal(t, tt.wantDepth, guide.Depth)
+			}
+		})
+	}
+}
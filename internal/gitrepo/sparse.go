@@ -0,0 +1,213 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package gitrepo
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	billyutil "github.com/go-git/go-billy/v5/util"
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+	"github.com/go-git/go-git/v5/plumbing/format/index"
+	"github.com/go-git/go-git/v5/utils/merkletrie"
+)
+
+// sparseCheckout describes which tracked paths are deliberately absent
+// from the working tree.
+//
+// git marks index entries outside the sparse checkout with the
+// skip-worktree bit and never looks at them in the working tree.  The
+// sparse-checkout patterns are consulted as well, for indexes that lost
+// the bits (e.g. after being rewritten by a tool that doesn't know
+// about them): a missing file the patterns exclude is not a deletion.
+type sparseCheckout struct {
+	skipWorktree map[string]bool
+
+	// patterns is nil unless core.sparseCheckout is enabled.
+	patterns sparsePatterns
+}
+
+// sparsePatterns reports whether a path is part of the sparse checkout.
+type sparsePatterns interface {
+	includes(name string) bool
+}
+
+// loadSparseCheckout collects the skip-worktree entries of idx and the
+// sparse-checkout patterns.  Returns nil when the working tree is not
+// sparse.
+func loadSparseCheckout(cx *Context, cfg effectiveConfig, idx *index.Index) (*sparseCheckout, error) {
+	sc := &sparseCheckout{skipWorktree: map[string]bool{}}
+	for _, e := range idx.Entries {
+		if e.SkipWorktree {
+			sc.skipWorktree[e.Name] = true
+		}
+	}
+
+	if cfg.sparseCheckout {
+		patterns, err := readSparsePatterns(cx, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("load sparse-checkout patterns: %w", err)
+		}
+		sc.patterns = patterns
+	}
+
+	if len(sc.skipWorktree) == 0 && sc.patterns == nil {
+		return nil, nil
+	}
+	return sc, nil
+}
+
+// hides reports whether ch is an artifact of the sparse checkout
+// rather than a change to the working tree.
+func (sc *sparseCheckout) hides(ch worktreeChange) bool {
+	switch {
+	case sc == nil || ch.action == merkletrie.Insert:
+		return false
+	case sc.skipWorktree[ch.path]:
+		return true
+	default:
+		return ch.action == merkletrie.Delete && sc.patterns != nil && !sc.patterns.includes(ch.path)
+	}
+}
+
+// readSparsePatterns reads $GIT_DIR/info/sparse-checkout, of which
+// every linked worktree has its own.  Returns nil when there is no such
+// file, in which case git treats every path as part of the checkout.
+func readSparsePatterns(cx *Context, cfg effectiveConfig) (sparsePatterns, error) {
+	fsys := cx.worktreeDotGitFilesystem()
+	if fsys == nil {
+		return nil, nil
+	}
+
+	content, err := billyutil.ReadFile(fsys, "info/sparse-checkout")
+	switch {
+	case isErrNotExist(err):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+
+	if cfg.sparseCheckoutCone {
+		if cone, ok := parseConePatterns(string(content)); ok {
+			return cone, nil
+		}
+		// git falls back to the full pattern syntax as well.
+	}
+
+	ps, err := readIgnoreFilePatterns(fsys, "info/sparse-checkout", nil, cfg.ignoreCase)
+	if err != nil {
+		return nil, err
+	}
+	return &fullSparsePatterns{patterns: ps, ignoreCase: cfg.ignoreCase}, nil
+}
+
+// fullSparsePatterns are sparse-checkout patterns in gitignore syntax,
+// where a match means the path is checked out.
+type fullSparsePatterns struct {
+	patterns   []gitignore.Pattern
+	ignoreCase bool
+}
+
+func (p *fullSparsePatterns) includes(name string) bool {
+	parts := splitGitPath(name, p.ignoreCase)
+
+	// The last pattern matching the path decides; paths no pattern
+	// matches inherit the decision for their parent directory.
+	for isDir := false; len(parts) > 0; parts, isDir = parts[:len(parts)-1], true {
+		for i := len(p.patterns) - 1; i >= 0; i-- {
+			switch p.patterns[i].Match(parts, isDir) {
+			case gitignore.Exclude:
+				return true
+			case gitignore.Include:
+				return false
+			}
+		}
+	}
+
+	return false
+}
+
+// conePatterns are sparse-checkout patterns in cone mode: every file
+// at the top level, all files below the recursive directories, and the
+// files directly inside their parent directories.
+type conePatterns struct {
+	recursive map[string]bool
+	parents   map[string]bool
+}
+
+// parseConePatterns parses the restricted pattern set written by `git
+// sparse-checkout set --cone`.  Reports false if content contains any
+// other kind of pattern.
+func parseConePatterns(content string) (*conePatterns, bool) {
+	cone := &conePatterns{recursive: map[string]bool{}, parents: map[string]bool{}}
+
+	for line := range strings.Lines(content) {
+		line = strings.TrimRight(line, "\r\n")
+		switch {
+		case line == "" || strings.HasPrefix(line, commentPrefix):
+			continue
+		case line == "/*" || line == "!/*/":
+			continue
+		case strings.HasPrefix(line, "!/") && strings.HasSuffix(line, "/*/"):
+			cone.parents[unescapeConePattern(line[2:len(line)-3])] = true
+		case strings.HasPrefix(line, "/") && strings.HasSuffix(line, "/") && len(line) > 2:
+			cone.recursive[unescapeConePattern(line[1:len(line)-1])] = true
+		default:
+			return nil, false
+		}
+	}
+
+	// A directory whose subdirectories are excluded is a parent, not
+	// a recursive directory.
+	for dir := range cone.parents {
+		delete(cone.recursive, dir)
+	}
+
+	return cone, true
+}
+
+func (c *conePatterns) includes(name string) bool {
+	dir := path.Dir(name)
+	if dir == "." || c.parents[dir] {
+		return true
+	}
+
+	for ; dir != "."; dir = path.Dir(dir) {
+		if c.recursive[dir] {
+			return true
+		}
+	}
+	return false
+}
+
+// unescapeConePattern removes the backslashes git adds in front of
+// glob characters in directory names.
+func unescapeConePattern(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,142 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package gitrepo_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0x5a17ed/semverkzeug/internal/gitfixture"
+	"github.com/0x5a17ed/semverkzeug/internal/gitrepo"
+)
+
+// markSkipWorktree sets the skip-worktree bit on the index entries
+// named, as `git sparse-checkout` does for paths outside the cone.
+func markSkipWorktree(t *testing.T, cx *gitrepo.Context, names ...string) {
+	t.Helper()
+
+	s := cx.Repository().Storer
+	idx, err := s.Index()
+	require.NoError(t, err)
+
+	for _, name := range names {
+		e, err := idx.Entry(name)
+		require.NoError(t, err)
+		e.SkipWorktree = true
+	}
+	require.NoError(t, s.SetIndex(idx))
+}
+
+func writeSparsePatterns(t *testing.T, cx *gitrepo.Context, cone bool, patterns string) {
+	t.Helper()
+
+	config := "[core]\n\tsparseCheckout = true\n"
+	if cone {
+		config += "\tsparseCheckoutCone = true\n"
+	}
+	writeRepoConfig(t, cx, config)
+
+	dotGit, ok := cx.DotGitPath()
+	require.True(t, ok)
+	gitfixture.WriteFile(t, filepath.Join(dotGit, "info", "sparse-checkout"), patterns)
+}
+
+func TestStatus_SparseCheckout(t *testing.T) {
+	_ = gitfixture.NewHomeFixture(t)
+	gitfixture.IsolateGitConfig(t)
+
+	tests := []struct {
+		name      string
+		arrange   func(t *testing.T, cx *gitrepo.Context)
+		wantDirty map[string]git.StatusCode
+	}{
+		{"skip-worktree-missing", func(t *testing.T, cx *gitrepo.Context) {
+			markSkipWorktree(t, cx, "out/file", "out/deep/file")
+			require.NoError(t, os.RemoveAll(repoFilePath(t, cx, "out")))
+		}, nil},
+		{"skip-worktree-present-and-different", func(t *testing.T, cx *gitrepo.Context) {
+			markSkipWorktree(t, cx, "out/file")
+			gitfixture.WriteRepoFile(t, cx, "out/file", "stale content")
+		}, nil},
+		{"cone-outside-missing", func(t *testing.T, cx *gitrepo.Context) {
+			writeSparsePatterns(t, cx, true, "/*\n!/*/\n/in/\n")
+			require.NoError(t, os.RemoveAll(repoFilePath(t, cx, "out")))
+		}, nil},
+		{"cone-inside-missing", func(t *testing.T, cx *gitrepo.Context) {
+			writeSparsePatterns(t, cx, true, "/*\n!/*/\n/in/\n")
+			require.NoError(t, os.Remove(repoFilePath(t, cx, "in/file")))
+		}, map[string]git.StatusCode{"in/file": git.Deleted}},
+		{"cone-parent-directory", func(t *testing.T, cx *gitrepo.Context) {
+			// Only the files directly inside out/ are checked out.
+			writeSparsePatterns(t, cx, true, "/*\n!/*/\n/out/\n!/out/*/\n")
+			require.NoError(t, os.RemoveAll(repoFilePath(t, cx, "out")))
+		}, map[string]git.StatusCode{"out/file": git.Deleted}},
+		{"cone-outside-modified", func(t *testing.T, cx *gitrepo.Context) {
+			// A file that is present is compared as usual.
+			writeSparsePatterns(t, cx, true, "/*\n!/*/\n/in/\n")
+			gitfixture.WriteRepoFile(t, cx, "out/file", "changed")
+		}, map[string]git.StatusCode{"out/file": git.Modified}},
+		{"non-cone-patterns", func(t *testing.T, cx *gitrepo.Context) {
+			writeSparsePatterns(t, cx, false, "/*\n!/out/deep/\n")
+			require.NoError(t, os.RemoveAll(repoFilePath(t, cx, "out/deep")))
+			require.NoError(t, os.Remove(repoFilePath(t, cx, "in/file")))
+		}, map[string]git.StatusCode{"in/file": git.Deleted}},
+		{"disabled-sparse-checkout", func(t *testing.T, cx *gitrepo.Context) {
+			writeSparsePatterns(t, cx, true, "/*\n!/*/\n/in/\n")
+			writeRepoConfig(t, cx, "[core]\n\tsparseCheckout = false\n")
+			require.NoError(t, os.Remove(repoFilePath(t, cx, "out/file")))
+		}, map[string]git.StatusCode{"out/file": git.Deleted}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			cx := gitfixture.RepoWithOneCommitNoTagsClean(t)
+			gitfixture.CommitFile(t, cx, "in/file", "baa")
+			gitfixture.CommitFile(t, cx, "out/file", "baa")
+			gitfixture.CommitFile(t, cx, "out/deep/file", "baa")
+			tt.arrange(t, cx)
+
+			for _, build := range []func(context.Context, *gitrepo.Context) (git.Status, error){
+				gitrepo.BuildWorktreeStatus,
+				gitrepo.BuildWorktreeStatusWithoutStatCache,
+			} {
+				// Act
+				st, err := build(t.Context(), cx)
+				require.NoError(t, err)
+
+				// Assert
+				got := map[string]git.StatusCode{}
+				for name, fs := range st {
+					if fs.Worktree != git.Unmodified {
+						got[name] = fs.Worktree
+					}
+				}
+				if tt.wantDirty == nil {
+					assert.Empty(t, got)
+				} else {
+					assert.Equal(t, tt.wantDirty, got)
+				}
+			}
+		})
+	}
+}
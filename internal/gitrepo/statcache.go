@@ -0,0 +1,469 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package gitrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-billy/v5/osfs"
+	billyutil "github.com/go-git/go-billy/v5/util"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/format/index"
+	"github.com/go-git/go-git/v5/utils/merkletrie"
+)
+
+// worktreeChange is one difference between the index and the
+// working tree.
+type worktreeChange struct {
+	path   string
+	action merkletrie.Action
+	isDir  bool
+}
+
+// statScan is the result of comparing the index with the working
+// tree using the stat data recorded in the index.
+type statScan struct {
+	changes []worktreeChange
+
+	// mtimes holds the modification time observed for every tracked
+	// path that still exists, so callers don't have to stat it again.
+	mtimes map[string]time.Time
+}
+
+// statusAccel holds what git recorded in the index to avoid looking
+// at the whole working tree: the fsmonitor extension combined with
+// the answer of the configured fsmonitor hook, and the untracked
+// cache extension.  Either is left unset when absent, disabled, or
+// not trustworthy, and the scan falls back to checking every path.
+type statusAccel struct {
+	cfg effectiveConfig
+
+	fsmonitorDirty   *ewahBitmap
+	fsmonitorChanges *fsmonitorChanges
+
+	untracked *untrackedCache
+}
+
+// load reads the index extensions and queries the fsmonitor hook.
+// Any failure merely disables the affected shortcut.
+func (a *statusAccel) load(ctx context.Context, cx *Context, idx *index.Index) {
+	dotGitFsys := cx.DotGitFilesystem()
+	if dotGitFsys == nil {
+		return
+	}
+
+	ext, err := readIndexExtensions(dotGitFsys)
+	if err != nil || ext == nil || ext.entryCount != len(idx.Entries) {
+		return
+	}
+
+	root, err := cx.LoadWorktreeRoot()
+	if err != nil {
+		return
+	}
+
+	if st := ext.fsmonitor; st != nil && isFSMonitorHook(a.cfg.fsmonitor) && st.dirty.size <= len(idx.Entries) {
+		if changes, err := queryFSMonitor(ctx, a.cfg.fsmonitor, root, st); err == nil {
+			a.fsmonitorDirty = st.dirty
+			a.fsmonitorChanges = changes
+		}
+	}
+
+	if uc := ext.untracked; uc != nil && (a.cfg.untrackedCache == nil || *a.cfg.untrackedCache) {
+		if a.untrackedCacheUsable(dotGitFsys, root, uc) {
+			a.untracked = uc
+		}
+	}
+}
+
+// untrackedCacheUsable checks that the untracked cache was recorded
+// for this worktree, with the global exclude files as they are now.
+func (a *statusAccel) untrackedCacheUsable(dotGitFsys billy.Filesystem, root string, uc *untrackedCache) bool {
+	if uc.root == nil || uc.excludeFileName != ".gitignore" {
+		return false
+	}
+
+	// git identifies the environment as "Location <worktree>, system
+	// <sysname>".
+	if !strings.HasPrefix(uc.ident, "Location "+root+", ") {
+		return false
+	}
+
+	infoExclude, err := hashIgnoreFile(dotGitFsys, "info/exclude")
+	if err != nil || infoExclude != uc.infoExcludeOID {
+		return false
+	}
+
+	excludesFile, err := resolveExcludesFile(a.cfg)
+	if err != nil {
+		return false
+	}
+	if excludesFile != "" {
+		if excludesFile, err = expandTilde(excludesFile); err != nil {
+			return false
+		}
+		if !filepath.IsAbs(excludesFile) {
+			excludesFile = filepath.Join(root, excludesFile)
+		}
+	}
+	globalExclude, err := hashIgnoreFile(osfs.New("/"), excludesFile)
+	if err != nil || globalExclude != uc.excludesFileOID {
+		return false
+	}
+
+	return true
+}
+
+// fsmonitorValid reports whether the fsmonitor vouches for the i-th
+// index entry being unchanged.
+func (a *statusAccel) fsmonitorValid(i int, name string) bool {
+	return a != nil && a.fsmonitorChanges != nil &&
+		!a.fsmonitorDirty.has(i) && !a.fsmonitorChanges.touches(name)
+}
+
+// hashIgnoreFile returns the hash of an exclude file the way the
+// untracked cache records it: zero when the file does not exist, the
+// empty blob for an empty file, and otherwise the blob hash of the
+// buffer git parses.  That is the content with a newline appended
+// (add_patterns in git's dir.c), so it is not the blob OID of the
+// file; the native git status tests depend on matching it exactly.
+func hashIgnoreFile(fsys billy.Filesystem, name string) (plumbing.Hash, error) {
+	if name == "" {
+		return plumbing.ZeroHash, nil
+	}
+
+	content, err := billyutil.ReadFile(fsys, name)
+	switch {
+	case isErrNotExist(err):
+		return plumbing.ZeroHash, nil
+	case err != nil:
+		return plumbing.ZeroHash, err
+	case len(content) > 0:
+		content = append(content, '\n')
+	}
+
+	return plumbing.ComputeHash(plumbing.BlobObject, content), nil
+}
+
+// canScanWithStatCache reports whether idx can be compared with the
+// working tree from stat data alone.  That requires knowing when the
+// index was written (to detect racily clean entries) and excludes
+// indexes holding submodules or unresolved merge conflicts, which
+// the merkletrie diff handles.
+func canScanWithStatCache(idx *index.Index) bool {
+	if idx.ModTime.IsZero() {
+		return false
+	}
+
+	for _, e := range idx.Entries {
+		if e.Mode == filemode.Submodule || e.Stage != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// scanWithStatCache compares the index with the working tree the way
+// `git status` does: an entry whose size, mtime, mode and inode match
+// what the index recorded is trusted to be unmodified, and only
+// entries with changed stat data, or which are racily clean (modified
+// no earlier than the index was written), have their content hashed.
+//
+// Entries the fsmonitor vouches for are not looked at at all.
+//
+// Untracked files are found by walking the working tree without
+// descending into ignored directories, reusing the untracked cache for
+// directories that did not change.
+func scanWithStatCache(ctx context.Context, wtFsys billy.Filesystem, idx *index.Index, matcher *ignoreMatcher, accel *statusAccel) (*statScan, error) {
+	scan := &statScan{
+		mtimes: make(map[string]time.Time, len(idx.Entries)),
+	}
+
+	tracked := make(map[string]bool, len(idx.Entries))
+	for i, e := range idx.Entries {
+		tracked[e.Name] = true
+
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		// Entries outside a sparse checkout are not expected in the
+		// working tree, whatever is found there.
+		if e.SkipWorktree || accel.fsmonitorValid(i, e.Name) {
+			continue
+		}
+
+		fi, err := wtFsys.Lstat(e.Name)
+		switch {
+		case errors.Is(err, fs.ErrNotExist) || errors.Is(err, syscall.ENOTDIR):
+			scan.changes = append(scan.changes, worktreeChange{path: e.Name, action: merkletrie.Delete})
+			continue
+		case err != nil:
+			return nil, fmt.Errorf("stat %q: %w", e.Name, err)
+		case fi.IsDir():
+			// Replaced by a directory; its content shows up as
+			// untracked files.
+			scan.changes = append(scan.changes, worktreeChange{path: e.Name, action: merkletrie.Delete})
+			continue
+		}
+
+		scan.mtimes[e.Name] = fi.ModTime().UTC()
+
+		modified, err := entryModified(wtFsys, idx, e, fi)
+		if err != nil {
+			return nil, err
+		}
+		if modified {
+			scan.changes = append(scan.changes, worktreeChange{path: e.Name, action: merkletrie.Modify})
+		}
+	}
+
+	u := &untrackedScan{
+		ctx:     ctx,
+		wtFsys:  wtFsys,
+		tracked: tracked,
+		matcher: matcher,
+		scan:    scan,
+	}
+	if accel != nil {
+		u.fsmonitor = accel.fsmonitorChanges
+	}
+
+	var err error
+	if accel != nil && accel.untracked != nil {
+		err = u.cachedDir(accel.untracked.root, "")
+	} else {
+		err = u.dir("")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return scan, nil
+}
+
+// entryModified reports whether the file described by fi differs
+// from the index entry e.
+func entryModified(wtFsys billy.Filesystem, idx *index.Index, e *index.Entry, fi os.FileInfo) (bool, error) {
+	mode, err := filemode.NewFromOSFileMode(fi.Mode())
+	if err != nil || mode != e.Mode {
+		return true, nil
+	}
+
+	if statMatches(idx, e, fi) {
+		return false, nil
+	}
+
+	h, err := hashWorktreeFile(wtFsys, e.Name, fi)
+	if err != nil {
+		return false, err
+	}
+
+	return h != e.Hash, nil
+}
+
+// statMatches reports whether the stat data of fi is what the index
+// recorded for e, and the entry is not racily clean.
+func statMatches(idx *index.Index, e *index.Entry, fi os.FileInfo) bool {
+	if uint32(fi.Size()) != e.Size || !fi.ModTime().Equal(e.ModifiedAt) {
+		return false
+	}
+
+	if dev, inode, ok := statIdentity(fi); ok && e.Inode != 0 {
+		if dev != e.Dev || inode != e.Inode {
+			return false
+		}
+	}
+
+	// A file modified in the same instant the index was written may
+	// have changed again without its mtime moving.
+	return fi.ModTime().Before(idx.ModTime)
+}
+
+func hashWorktreeFile(wtFsys billy.Filesystem, name string, fi os.FileInfo) (plumbing.Hash, error) {
+	h := plumbing.NewHasher(plumbing.BlobObject, fi.Size())
+
+	if fi.Mode()&os.ModeSymlink != 0 {
+		target, err := wtFsys.Readlink(name)
+		if err != nil {
+			return plumbing.ZeroHash, fmt.Errorf("read link %q: %w", name, err)
+		}
+		_, _ = h.Write([]byte(target))
+		return h.Sum(), nil
+	}
+
+	f, err := wtFsys.Open(name)
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("open %q: %w", name, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	if _, err := io.Copy(h, f); err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("hash %q: %w", name, err)
+	}
+
+	return h.Sum(), nil
+}
+
+// untrackedScan collects the untracked files of the working tree.
+type untrackedScan struct {
+	ctx       context.Context
+	wtFsys    billy.Filesystem
+	tracked   map[string]bool
+	matcher   *ignoreMatcher
+	fsmonitor *fsmonitorChanges
+	scan      *statScan
+}
+
+func (u *untrackedScan) add(name string) {
+	u.scan.changes = append(u.scan.changes, worktreeChange{path: name, action: merkletrie.Insert})
+}
+
+// dir records every file below dir that is neither tracked nor
+// ignored.
+func (u *untrackedScan) dir(dir string) error {
+	if err := u.ctx.Err(); err != nil {
+		return err
+	}
+
+	entries, err := u.wtFsys.ReadDir(dir)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return nil
+	case err != nil:
+		return fmt.Errorf("read directory %q: %w", dir, err)
+	}
+
+	for _, fi := range entries {
+		name := path.Join(dir, fi.Name())
+		switch {
+		case fi.Name() == ".git":
+			// Like go-git, skip the git directories of nested
+			// repositories, too.
+			continue
+		case fi.Mode()&os.ModeSocket != 0:
+			continue
+		case fi.IsDir():
+			if u.matcher.Match(name, true) {
+				continue
+			}
+			if err := u.dir(name); err != nil {
+				return err
+			}
+		case !u.tracked[name] && !u.matcher.Match(name, false):
+			u.add(name)
+		}
+	}
+
+	return nil
+}
+
+// cachedDir is dir for a directory with an untracked cache block.
+// The cached listing is used as long as the directory and its
+// .gitignore are unchanged; otherwise the directory is read again.
+func (u *untrackedScan) cachedDir(d *untrackedDir, dir string) error {
+	if err := u.ctx.Err(); err != nil {
+		return err
+	}
+
+	fresh, err := u.cacheFresh(d, dir)
+	if err != nil {
+		return err
+	}
+	if !fresh {
+		return u.dir(dir)
+	}
+
+	untrackedDirs := map[string]bool{}
+	for _, entry := range d.untracked {
+		name := path.Join(dir, strings.TrimSuffix(entry, "/"))
+		switch {
+		case strings.HasSuffix(entry, "/"):
+			// A wholly untracked directory is listed as one
+			// entry; its files are listed individually here.
+			untrackedDirs[name] = true
+			if !u.matcher.Match(name, true) {
+				if err := u.dir(name); err != nil {
+					return err
+				}
+			}
+		case !u.tracked[name] && !u.matcher.Match(name, false):
+			u.add(name)
+		}
+	}
+
+	for _, sub := range d.dirs {
+		name := path.Join(dir, sub.name)
+		if untrackedDirs[name] || u.matcher.Match(name, true) {
+			continue
+		}
+		if err := u.cachedDir(sub, name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// cacheFresh reports whether the cached listing of dir still holds.
+func (u *untrackedScan) cacheFresh(d *untrackedDir, dir string) (bool, error) {
+	if !d.valid || d.checkOnly {
+		return false, nil
+	}
+
+	excludeOID, err := hashIgnoreFile(u.wtFsys, path.Join(dir, ".gitignore"))
+	if err != nil {
+		return false, err
+	}
+	if excludeOID != d.excludeOID {
+		return false, nil
+	}
+
+	if u.fsmonitor != nil {
+		return !u.fsmonitor.dirChanged(dir), nil
+	}
+
+	fi, err := u.wtFsys.Lstat(dirOrRoot(dir))
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("stat %q: %w", dir, err)
+	}
+
+	return fi.IsDir() && fi.ModTime().Equal(d.mtime), nil
+}
+
+func dirOrRoot(dir string) string {
+	if dir == "" {
+		return "."
+	}
+	return dir
+}
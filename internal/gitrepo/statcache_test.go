@@ -0,0 +1,141 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package gitrepo_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0x5a17ed/semverkzeug/internal/gitfixture"
+	"github.com/0x5a17ed/semverkzeug/internal/gitrepo"
+)
+
+func repoFilePath(t *testing.T, cx *gitrepo.Context, name string) string {
+	t.Helper()
+
+	return filepath.Join(gitfixture.Filesystem(t, cx).Root(), name)
+}
+
+// TestStatus_StatCacheMatchesContentComparison checks the stat cache
+// fast path against the full content comparison.
+func TestStatus_StatCacheMatchesContentComparison(t *testing.T) {
+	tests := []struct {
+		name    string
+		arrange func(t *testing.T, cx *gitrepo.Context)
+	}{
+		{"clean", func(t *testing.T, cx *gitrepo.Context) {}},
+		{"modified", func(t *testing.T, cx *gitrepo.Context) {
+			gitfixture.WriteRepoFile(t, cx, "foo", "changed content")
+		}},
+		{"touched-unchanged", func(t *testing.T, cx *gitrepo.Context) {
+			future := time.Now().Add(time.Hour)
+			require.NoError(t, os.Chtimes(repoFilePath(t, cx, "foo"), future, future))
+		}},
+		{"deleted", func(t *testing.T, cx *gitrepo.Context) {
+			require.NoError(t, os.Remove(repoFilePath(t, cx, "dir/bar")))
+		}},
+		{"untracked", func(t *testing.T, cx *gitrepo.Context) {
+			gitfixture.WriteRepoFile(t, cx, "new", "baa")
+			gitfixture.WriteRepoFile(t, cx, "dir/sub/new", "baa")
+		}},
+		{"ignored", func(t *testing.T, cx *gitrepo.Context) {
+			gitfixture.CommitFile(t, cx, ".gitignore", "build/\n*.log\n")
+			gitfixture.WriteRepoFile(t, cx, "build/out", "baa")
+			gitfixture.WriteRepoFile(t, cx, "dir/debug.log", "baa")
+			gitfixture.WriteRepoFile(t, cx, "dir/keep", "baa")
+		}},
+		{"staged-then-modified", func(t *testing.T, cx *gitrepo.Context) {
+			gitfixture.WriteRepoFile(t, cx, "staged", "baa")
+			_, err := gitfixture.Worktree(t, cx).Add("staged")
+			require.NoError(t, err)
+			gitfixture.WriteRepoFile(t, cx, "staged", "baz baz")
+		}},
+		{"mode-changed", func(t *testing.T, cx *gitrepo.Context) {
+			require.NoError(t, os.Chmod(repoFilePath(t, cx, "foo"), 0o755))
+		}},
+		{"replaced-by-directory", func(t *testing.T, cx *gitrepo.Context) {
+			require.NoError(t, os.Remove(repoFilePath(t, cx, "foo")))
+			gitfixture.WriteRepoFile(t, cx, "foo/inner", "baa")
+		}},
+		{"symlink", func(t *testing.T, cx *gitrepo.Context) {
+			require.NoError(t, os.Symlink("foo", repoFilePath(t, cx, "link")))
+		}},
+		{"nested-repository", func(t *testing.T, cx *gitrepo.Context) {
+			// Billy refuses paths through .git, so write them directly.
+			require.NoError(t, os.MkdirAll(repoFilePath(t, cx, "vendor/x/.git"), 0o755))
+			require.NoError(t, os.WriteFile(repoFilePath(t, cx, "vendor/x/.git/HEAD"), []byte("ref: refs/heads/main\n"), 0o644))
+			require.NoError(t, os.WriteFile(repoFilePath(t, cx, "dir/.git"), []byte("gitdir: ../.git/modules/dir\n"), 0o644))
+			gitfixture.WriteRepoFile(t, cx, "vendor/x/lib.go", "package x\n")
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			cx := gitfixture.RepoWithOneCommitNoTagsClean(t)
+			gitfixture.CommitFile(t, cx, "dir/bar", "baa")
+			tt.arrange(t, cx)
+
+			// Act
+			want, err := gitrepo.BuildWorktreeStatusWithoutStatCache(t.Context(), cx)
+			require.NoError(t, err)
+
+			got, err := gitrepo.BuildWorktreeStatus(t.Context(), cx)
+			require.NoError(t, err)
+
+			// Assert
+			assert.Equal(t, want, got, "want:\n%s\ngot:\n%s", want, got)
+		})
+	}
+}
+
+// TestStatus_StatCacheHashesRacilyCleanFiles verifies that a file
+// whose stat data still matches the index is compared by content when
+// it was modified no earlier than the index was written.
+func TestStatus_StatCacheHashesRacilyCleanFiles(t *testing.T) {
+	// Arrange: Commit a file with an mtime ahead of the index, then
+	// change its content without changing size or mtime.
+	cx := gitfixture.RepoEmpty(t)
+	p := repoFilePath(t, cx, "foo")
+
+	future := time.Now().Add(time.Hour).Truncate(time.Second)
+	gitfixture.WriteRepoFile(t, cx, "foo", "aaa")
+	require.NoError(t, os.Chtimes(p, future, future))
+
+	_, err := gitfixture.Worktree(t, cx).Add("foo")
+	require.NoError(t, err)
+	_, err = gitfixture.Worktree(t, cx).Commit("commit foo", &git.CommitOptions{
+		Author:    gitfixture.TestSig,
+		Committer: gitfixture.TestSig,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(p, []byte("bbb"), 0o644))
+	require.NoError(t, os.Chtimes(p, future, future))
+
+	// Act
+	st, err := gitrepo.BuildWorktreeStatus(t.Context(), cx)
+	require.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, git.Modified, requireStatus(t, st, "foo").Worktree)
+}
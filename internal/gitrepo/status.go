@@ -18,8 +18,11 @@ package gitrepo
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"time"
 
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
@@ -34,36 +37,82 @@ import (
 // context by comparing the working tree state with the index using Git-compatible
 // ignore precedence for untracked files.
 // Returns a [git.Status] or an error.
-func BuildWorktreeStatus(cx *Context) (git.Status, error) {
-	matcher, err := loadIgnoreMatcher(cx)
+func BuildWorktreeStatus(ctx context.Context, cx *Context) (git.Status, error) {
+	st, _, err := buildWorktreeStatus(ctx, cx, true)
+	return st, err
+}
+
+// buildWorktreeStatus is BuildWorktreeStatus that also returns the
+// modification times observed while comparing the working tree, keyed
+// by path.  The stat cache of the index is only consulted when
+// useStatCache is set.
+func buildWorktreeStatus(ctx context.Context, cx *Context, useStatCache bool) (git.Status, map[string]time.Time, error) {
+	cfg, err := loadEffectiveConfig(cx)
 	if err != nil {
-		return nil, fmt.Errorf("load gitignore matcher: %w", err)
+		return nil, nil, err
+	}
+
+	matcher, err := loadIgnoreMatcher(cx, cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("load gitignore matcher: %w", err)
 	}
 
 	head := plumbing.ZeroHash
 	ref, err := cx.Repository().Head()
 	if err != nil && !errors.Is(err, plumbing.ErrReferenceNotFound) {
-		return nil, fmt.Errorf("resolve HEAD: %w", err)
+		return nil, nil, fmt.Errorf("resolve HEAD: %w", err)
 	}
 	if err == nil {
 		head = ref.Hash()
 	}
 
-	return status(cx, head, matcher)
+	idx, err := cx.Repository().Storer.Index()
+	if err != nil {
+		return nil, nil, fmt.Errorf("load index: %w", err)
+	}
+
+	sparse, err := loadSparseCheckout(cx, cfg, idx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var accel *statusAccel
+	if useStatCache {
+		accel = &statusAccel{cfg: cfg}
+	}
+
+	st, mtimes, err := status(ctx, cx, head, matcher, sparse, accel)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mtimes, err = applySubmodulePolicy(ctx, cx, cfg, idx, st, mtimes)
+	if err != nil {
+		return nil, nil, err
+	}
+	slog.DebugContext(ctx, "build worktree status", "changes", len(st), "stat_cache", useStatCache, "sparse", sparse != nil)
+	return st, mtimes, nil
 }
 
-func status(cx *Context, commit plumbing.Hash, matcher *ignoreMatcher) (git.Status, error) {
+func status(
+	ctx context.Context,
+	cx *Context,
+	commit plumbing.Hash,
+	matcher *ignoreMatcher,
+	sparse *sparseCheckout,
+	accel *statusAccel,
+) (git.Status, map[string]time.Time, error) {
 	st := make(git.Status)
 
-	left, err := diffCommitWithStaging(cx.Repository(), commit, false)
+	left, err := diffCommitWithStaging(ctx, cx.Repository(), commit, false)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	for _, ch := range left {
 		action, err := ch.Action()
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		fs := st.File(nameFromAction(&ch))
@@ -79,27 +128,25 @@ func status(cx *Context, commit plumbing.Hash, matcher *ignoreMatcher) (git.Stat
 		}
 	}
 
-	right, err := diffStagingWithWorktree(cx, false)
+	right, mtimes, err := diffStagingWithWorktreeChanges(ctx, cx, matcher, accel)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	for _, ch := range right {
-		action, err := ch.Action()
-		if err != nil {
-			return nil, err
+		if ch.action == merkletrie.Insert && matcher.Match(ch.path, ch.isDir) {
+			continue
 		}
-
-		if action == merkletrie.Insert && matcher.Match(nameFromAction(&ch), changeIsDir(&ch)) {
+		if sparse.hides(ch) {
 			continue
 		}
 
-		fs := st.File(nameFromAction(&ch))
+		fs := st.File(ch.path)
 		if fs.Staging == git.Untracked {
 			fs.Staging = git.Unmodified
 		}
 
-		switch action {
+		switch ch.action {
 		case merkletrie.Delete:
 			fs.Worktree = git.Deleted
 		case merkletrie.Insert:
@@ -110,10 +157,58 @@ func status(cx *Context, commit plumbing.Hash, matcher *ignoreMatcher) (git.Stat
 		}
 	}
 
-	return st, nil
+	return st, mtimes, nil
+}
+
+// diffStagingWithWorktreeChanges lists the differences between the
+// index and the working tree, using the stat cache of the index (and
+// the fsmonitor and untracked cache extensions, see statusAccel) when
+// accel is given and a full content comparison otherwise.
+func diffStagingWithWorktreeChanges(ctx context.Context, cx *Context, matcher *ignoreMatcher, accel *statusAccel) ([]worktreeChange, map[string]time.Time, error) {
+	if accel != nil {
+		idx, err := cx.Repository().Storer.Index()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if canScanWithStatCache(idx) {
+			wtFsys, err := cx.LoadWorktreeFilesystem()
+			if err != nil {
+				return nil, nil, err
+			}
+
+			accel.load(ctx, cx, idx)
+
+			scan, err := scanWithStatCache(ctx, wtFsys, idx, matcher, accel)
+			if err != nil {
+				return nil, nil, err
+			}
+			return scan.changes, scan.mtimes, nil
+		}
+	}
+
+	changes, err := diffStagingWithWorktree(ctx, cx, false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	out := make([]worktreeChange, 0, len(changes))
+	for _, ch := range changes {
+		action, err := ch.Action()
+		if err != nil {
+			return nil, nil, err
+		}
+		out = append(out, worktreeChange{
+			path:   nameFromAction(&ch),
+			action: action,
+			isDir:  changeIsDir(&ch),
+		})
+	}
+
+	return out, nil, nil
 }
 
-func diffCommitWithStaging(repo *git.Repository, commit plumbing.Hash, reverse bool) (merkletrie.Changes, error) {
+func diffCommitWithStaging(ctx context.Context, repo *git.Repository, commit plumbing.Hash, reverse bool) (merkletrie.Changes, error) {
 	var tree *object.Tree
 	if !commit.IsZero() {
 		c, err := repo.CommitObject(commit)
@@ -127,10 +222,10 @@ func diffCommitWithStaging(repo *git.Repository, commit plumbing.Hash, reverse b
 		}
 	}
 
-	return diffTreeWithStaging(repo, tree, reverse)
+	return diffTreeWithStaging(ctx, repo, tree, reverse)
 }
 
-func diffTreeWithStaging(repo *git.Repository, tree *object.Tree, reverse bool) (merkletrie.Changes, error) {
+func diffTreeWithStaging(ctx context.Context, repo *git.Repository, tree *object.Tree, reverse bool) (merkletrie.Changes, error) {
 	var from noder.Noder
 	if tree != nil {
 		from = object.NewTreeRootNode(tree)
@@ -142,14 +237,10 @@ func diffTreeWithStaging(repo *git.Repository, tree *object.Tree, reverse bool)
 	}
 
 	to := indexnode.NewRootNode(idx)
-	if reverse {
-		return merkletrie.DiffTree(to, from, diffTreeIsEqual)
-	}
-
-	return merkletrie.DiffTree(from, to, diffTreeIsEqual)
+	return diffTree(ctx, from, to, reverse)
 }
 
-func diffStagingWithWorktree(cx *Context, reverse bool) (merkletrie.Changes, error) {
+func diffStagingWithWorktree(ctx context.Context, cx *Context, reverse bool) (merkletrie.Changes, error) {
 	idx, err := cx.Repository().Storer.Index()
 	if err != nil {
 		return nil, err
@@ -168,11 +259,22 @@ func diffStagingWithWorktree(cx *Context, reverse bool) (merkletrie.Changes, err
 	}
 	to := fsnode.NewRootNodeWithOptions(wt.Filesystem, submodules, fsnode.Options{Index: idx})
 
+	return diffTree(ctx, from, to, reverse)
+}
+
+// diffTree compares the trees from and to, or to and from when
+// reverse is true.  Unlike merkletrie.DiffTreeContext, it returns the
+// error of ctx once ctx is done.
+func diffTree(ctx context.Context, from, to noder.Noder, reverse bool) (merkletrie.Changes, error) {
 	if reverse {
-		return merkletrie.DiffTree(to, from, diffTreeIsEqual)
+		from, to = to, from
 	}
 
-	return merkletrie.DiffTree(from, to, diffTreeIsEqual)
+	changes, err := merkletrie.DiffTreeContext(ctx, from, to, diffTreeIsEqual)
+	if errors.Is(err, merkletrie.ErrCanceled) {
+		return nil, ctx.Err()
+	}
+	return changes, err
 }
 
 func submoduleStatus(wt *git.Worktree) (map[string]plumbing.Hash, error) {
@@ -17,6 +17,7 @@
 package gitrepo_test
 
 import (
+	"context"
 	"path/filepath"
 	"testing"
 
@@ -52,7 +53,7 @@ func TestStatus_HonorsDefaultXDGIgnore(t *testing.T) {
 
 	gitfixture.WriteRepoFile(t, cx, "local/file", "ignored\n")
 
-	st, err := gitrepo.BuildWorktreeStatus(cx)
+	st, err := gitrepo.BuildWorktreeStatus(t.Context(), cx)
 	require.NoError(t, err)
 
 	// Assert: status should not contain ignored file.
@@ -76,7 +77,7 @@ func TestStatus_CoreExcludesFileReplacesDefaultXDGIgnore(t *testing.T) {
 	gitfixture.WriteRepoFile(t, cx, "custom/file", "ignored\n")
 	gitfixture.WriteRepoFile(t, cx, "xdgonly/file", "visible\n")
 
-	st, err := gitrepo.BuildWorktreeStatus(cx)
+	st, err := gitrepo.BuildWorktreeStatus(t.Context(), cx)
 	require.NoError(t, err)
 	assert.NotContains(t, st, "custom/file")
 	assert.Equal(t, git.Untracked, requireStatus(t, st, "xdgonly/file").Worktree)
@@ -107,7 +108,7 @@ func TestStatus_IncludeIfGitdirExpandsHome(t *testing.T) {
 	gitfixture.CommitFile(t, cx, "tracked", "tracked\n")
 	gitfixture.WriteRepoFile(t, cx, "local/file", "ignored\n")
 
-	st, err := gitrepo.BuildWorktreeStatus(cx)
+	st, err := gitrepo.BuildWorktreeStatus(t.Context(), cx)
 	require.NoError(t, err)
 	assert.NotContains(t, st, "local/file")
 	assert.True(t, st.IsClean(), "status:\n%s", st.String())
@@ -123,7 +124,7 @@ func TestStatus_GitignoreOverridesGlobalExclude(t *testing.T) {
 	gitfixture.CommitFile(t, cx, ".gitignore", "!local/\n")
 	gitfixture.WriteRepoFile(t, cx, "local/file", "visible\n")
 
-	st, err := gitrepo.BuildWorktreeStatus(cx)
+	st, err := gitrepo.BuildWorktreeStatus(t.Context(), cx)
 	require.NoError(t, err)
 	assert.Equal(t, git.Untracked, requireStatus(t, st, "local/file").Worktree)
 }
@@ -138,7 +139,7 @@ func TestStatus_DoesNotReincludeInsideIgnoredParent(t *testing.T) {
 	gitfixture.CommitFile(t, cx, ".gitignore", "!local/file\n")
 	gitfixture.WriteRepoFile(t, cx, "local/file", "ignored\n")
 
-	st, err := gitrepo.BuildWorktreeStatus(cx)
+	st, err := gitrepo.BuildWorktreeStatus(t.Context(), cx)
 	require.NoError(t, err)
 	assert.NotContains(t, st, "local/file")
 	assert.True(t, st.IsClean(), "status:\n%s", st.String())
@@ -154,7 +155,7 @@ func TestStatus_DoesNotUseGitignoreInsideIgnoredDirectoryToReinclude(t *testing.
 	gitfixture.WriteRepoFile(t, cx, "ignored/.gitignore", "!file\n")
 	gitfixture.WriteRepoFile(t, cx, "ignored/file", "ignored\n")
 
-	st, err := gitrepo.BuildWorktreeStatus(cx)
+	st, err := gitrepo.BuildWorktreeStatus(t.Context(), cx)
 	require.NoError(t, err)
 	assert.NotContains(t, st, "ignored/file")
 	assert.True(t, st.IsClean(), "status:\n%s", st.String())
@@ -169,7 +170,7 @@ func TestStatus_ShowsTrackedFileEvenWhenIgnored(t *testing.T) {
 	gitfixture.CommitFile(t, cx, ".gitignore", "foo\n")
 	gitfixture.WriteRepoFile(t, cx, "foo", "new\n")
 
-	st, err := gitrepo.BuildWorktreeStatus(cx)
+	st, err := gitrepo.BuildWorktreeStatus(t.Context(), cx)
 	require.NoError(t, err)
 	assert.Equal(t, git.Modified, requireStatus(t, st, "foo").Worktree)
 	assert.Equal(t, git.Unmodified, requireStatus(t, st, "foo").Staging)
@@ -198,7 +199,7 @@ func TestStatus_LocalCoreExcludesFileOverridesGlobal(t *testing.T) {
 	gitfixture.WriteRepoFile(t, cx, "globalonly/file", "visible\n")
 	gitfixture.WriteRepoFile(t, cx, "localonly/file", "ignored\n")
 
-	st, err := gitrepo.BuildWorktreeStatus(cx)
+	st, err := gitrepo.BuildWorktreeStatus(t.Context(), cx)
 	require.NoError(t, err)
 	assert.Equal(t, git.Untracked, requireStatus(t, st, "globalonly/file").Worktree)
 	assert.NotContains(t, st, "localonly/file")
@@ -219,8 +220,29 @@ func TestStatus_HonorsCoreIgnoreCase(t *testing.T) {
 
 	gitfixture.WriteRepoFile(t, cx, "build/file", "ignored\n")
 
-	st, err := gitrepo.BuildWorktreeStatus(cx)
+	st, err := gitrepo.BuildWorktreeStatus(t.Context(), cx)
 	require.NoError(t, err)
 	assert.NotContains(t, st, "build/file")
 	assert.True(t, st.IsClean(), "status:\n%s", st.String())
 }
+
+func TestStatus_Canceled(t *testing.T) {
+	// Arrange
+	cx := gitfixture.RepoWithOneCommitNoTagsClean(t)
+	gitfixture.WriteRepoFile(t, cx, "foo", "baz")
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	for _, build := range []func(context.Context, *gitrepo.Context) (git.Status, error){
+		gitrepo.BuildWorktreeStatus,
+		gitrepo.BuildWorktreeStatusWithoutStatCache,
+	} {
+		// Act
+		st, err := build(ctx, cx)
+
+		// Assert
+		require.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, st)
+	}
+}
@@ -0,0 +1,284 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package gitrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/format/index"
+)
+
+// SubmodulePolicy selects how submodules affect the worktree status,
+// and with it whether a version is dirty.  The policies mirror the
+// values of git's diff.ignoreSubmodules.
+type SubmodulePolicy int
+
+const (
+	// SubmodulesConfigured follows diff.ignoreSubmodules, and
+	// SubmodulesFullRecursive when that is unset, like git does.
+	SubmodulesConfigured SubmodulePolicy = iota
+
+	// SubmodulesIgnore ignores submodules entirely, even a staged
+	// change of their commit ("all").
+	SubmodulesIgnore
+
+	// SubmodulesCommitOnly only considers whether a submodule has
+	// another commit checked out than recorded ("dirty").
+	SubmodulesCommitOnly
+
+	// SubmodulesFullRecursive also considers the modified and
+	// untracked files of submodules, recursively ("none").
+	SubmodulesFullRecursive
+)
+
+// ParseSubmodulePolicy parses a policy name, or one of the values of
+// git's diff.ignoreSubmodules.  git's "untracked" has no equivalent;
+// it is treated like "none".
+func ParseSubmodulePolicy(s string) (SubmodulePolicy, error) {
+	switch strings.ToLower(s) {
+	case "":
+		return SubmodulesConfigured, nil
+	case "ignore", "all":
+		return SubmodulesIgnore, nil
+	case "commit-only", "dirty":
+		return SubmodulesCommitOnly, nil
+	case "full-recursive", "none", "untracked":
+		return SubmodulesFullRecursive, nil
+	}
+	return SubmodulesConfigured, fmt.Errorf("unknown submodule policy %q", s)
+}
+
+func (p SubmodulePolicy) String() string {
+	switch p {
+	case SubmodulesIgnore:
+		return "ignore"
+	case SubmodulesCommitOnly:
+		return "commit-only"
+	case SubmodulesFullRecursive:
+		return "full-recursive"
+	}
+	return ""
+}
+
+// effectiveSubmodulePolicy resolves SubmodulesConfigured.
+func effectiveSubmodulePolicy(cx *Context, cfg effectiveConfig) (SubmodulePolicy, error) {
+	if cx.submodules != SubmodulesConfigured {
+		return cx.submodules, nil
+	}
+
+	p, err := ParseSubmodulePolicy(cfg.ignoreSubmodules)
+	if err != nil {
+		return p, fmt.Errorf("parse diff.ignoreSubmodules: %w", err)
+	}
+	if p == SubmodulesConfigured {
+		return SubmodulesFullRecursive, nil
+	}
+	return p, nil
+}
+
+// Submodule is a checked out submodule of a repository.
+type Submodule struct {
+	// Path is the path of the submodule relative to the worktree
+	// root of the top-level repository.
+	Path string
+
+	// Context is the repository of the submodule.  It uses the
+	// submodule policy of the top-level repository.
+	Context *Context
+}
+
+// LoadSubmodules opens the checked out submodules of the repository
+// and, recursively, of those submodules, in index order.  Submodules
+// that were never initialized are skipped.
+func LoadSubmodules(cx *Context) ([]Submodule, error) {
+	idx, err := cx.Repository().Storer.Index()
+	if err != nil {
+		return nil, fmt.Errorf("load index: %w", err)
+	}
+
+	var out []Submodule
+	for _, p := range submodulePaths(idx) {
+		sub, err := openSubmodule(cx, p)
+		if err != nil {
+			return nil, err
+		}
+		if sub == nil {
+			continue
+		}
+		out = append(out, Submodule{Path: p, Context: sub})
+
+		nested, err := LoadSubmodules(sub)
+		if err != nil {
+			return nil, fmt.Errorf("submodule %s: %w", p, err)
+		}
+		for _, n := range nested {
+			n.Path = path.Join(p, n.Path)
+			out = append(out, n)
+		}
+	}
+
+	return out, nil
+}
+
+// submodulePaths lists the submodules recorded in idx.
+func submodulePaths(idx *index.Index) []string {
+	var out []string
+	for _, e := range idx.Entries {
+		if e.Mode == filemode.Submodule && e.Stage == 0 {
+			out = append(out, e.Name)
+		}
+	}
+	return out
+}
+
+// openSubmodule opens the submodule at p, relative to the worktree
+// root.  Returns nil when the submodule is not checked out.
+func openSubmodule(cx *Context, p string) (*Context, error) {
+	root, err := cx.LoadWorktreeRoot()
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Join(root, filepath.FromSlash(p))
+	if _, err := os.Lstat(filepath.Join(dir, git.GitDirName)); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	sub, err := NewContextFromPath(dir)
+	if err != nil {
+		return nil, fmt.Errorf("open submodule %s: %w", p, err)
+	}
+	sub.submodules = cx.submodules
+	return sub, nil
+}
+
+// applySubmodulePolicy adjusts the status of the submodules recorded
+// in idx to the submodule policy.  Submodules that are ignored are
+// removed from st.  With SubmodulesFullRecursive, the dirty files of
+// submodules are added to st, and their modification times to mtimes,
+// under the path of the submodule.
+//
+// A submodule with another commit checked out is dated by that
+// commit as well: checking out a commit doesn't change the
+// modification time of the submodule directory.
+func applySubmodulePolicy(
+	ctx context.Context,
+	cx *Context,
+	cfg effectiveConfig,
+	idx *index.Index,
+	st git.Status,
+	mtimes map[string]time.Time,
+) (map[string]time.Time, error) {
+	paths := submodulePaths(idx)
+	if len(paths) == 0 {
+		return mtimes, nil
+	}
+
+	policy, err := effectiveSubmodulePolicy(cx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if mtimes == nil {
+		mtimes = map[string]time.Time{}
+	}
+
+	for _, p := range paths {
+		if policy == SubmodulesIgnore {
+			delete(st, p)
+			continue
+		}
+
+		fst, changed := st[p]
+		moved := changed && fst.Worktree == git.Modified
+		if !moved && policy != SubmodulesFullRecursive {
+			continue
+		}
+
+		sub, err := openSubmodule(cx, p)
+		if err != nil {
+			return nil, err
+		}
+		if sub == nil {
+			continue
+		}
+
+		if moved {
+			if err := dateSubmoduleCommit(cx, sub, p, mtimes); err != nil {
+				return nil, err
+			}
+		}
+
+		if policy != SubmodulesFullRecursive {
+			continue
+		}
+
+		subStatus, subMTimes, err := buildWorktreeStatus(ctx, sub, true)
+		if err != nil {
+			return nil, fmt.Errorf("submodule %s: %w", p, err)
+		}
+		for name, s := range subStatus {
+			if s.Worktree == git.Unmodified && s.Staging == git.Unmodified {
+				continue
+			}
+
+			full := path.Join(p, name)
+			st[full] = s
+			if mtime, ok := subMTimes[name]; ok {
+				mtimes[full] = mtime
+			}
+		}
+	}
+
+	return mtimes, nil
+}
+
+// dateSubmoduleCommit records the later of the modification time of
+// the submodule directory and the commit time of its HEAD in mtimes.
+func dateSubmoduleCommit(cx *Context, sub *Context, p string, mtimes map[string]time.Time) error {
+	wtFsys, err := cx.LoadWorktreeFilesystem()
+	if err != nil {
+		return err
+	}
+
+	mtime, err := findMTimePath(wtFsys, p)
+	if err != nil {
+		return fmt.Errorf("find mtime for %q: %w", p, err)
+	}
+
+	if head, err := sub.Repository().Head(); err == nil {
+		if c, err := sub.Repository().CommitObject(head.Hash()); err == nil && c.Committer.When.After(mtime) {
+			mtime = c.Committer.When.UTC()
+		}
+	}
+
+	mtimes[p] = mtime
+	return nil
+}
@@ -0,0 +1,186 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package gitrepo_test
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0x5a17ed/semverkzeug/internal/gitfixture"
+	"github.com/0x5a17ed/semverkzeug/internal/gitrepo"
+)
+
+// newSuperproject creates a repository with the submodule "sub",
+// which itself has the submodule "nested".  Returns its path.
+func newSuperproject(t *testing.T) string {
+	t.Helper()
+
+	newRepo := func(name string) string {
+		dir := filepath.Join(t.TempDir(), name)
+		gitfixture.RunGit(t, filepath.Dir(dir), "init", "-q", "-b", "main", dir)
+		gitfixture.WriteFile(t, filepath.Join(dir, "foo"), "baa")
+		gitfixture.RunGit(t, dir, "add", ".")
+		gitfixture.RunGit(t, dir, "commit", "-q", "-m", "initial")
+		return dir
+	}
+
+	addSubmodule := func(dir, src, name string) {
+		gitfixture.RunGit(t, dir, "-c", "protocol.file.allow=always", "submodule", "add", "-q", "file://"+src, name)
+		gitfixture.RunGit(t, dir, "commit", "-q", "-m", "add "+name)
+	}
+
+	nested := newRepo("nested")
+	sub := newRepo("sub")
+	addSubmodule(sub, nested, "nested")
+	gitfixture.RunGit(t, sub, "tag", "-a", "-m", "v1.0.0", "v1.0.0")
+
+	top := newRepo("top")
+	gitfixture.RunGit(t, top, "-c", "protocol.file.allow=always", "submodule", "add", "-q", "file://"+sub, "sub")
+	gitfixture.RunGit(t, top, "-c", "protocol.file.allow=always", "submodule", "update", "-q", "--init", "--recursive")
+	gitfixture.RunGit(t, top, "commit", "-q", "-m", "add sub")
+	return top
+}
+
+func TestStatus_SubmodulePolicy(t *testing.T) {
+	_ = gitfixture.NewHomeFixture(t)
+	gitfixture.IsolateGitConfig(t)
+
+	scenarios := map[string]func(t *testing.T, top string){
+		"clean": func(t *testing.T, top string) {},
+		"modified": func(t *testing.T, top string) {
+			gitfixture.WriteFile(t, filepath.Join(top, "sub", "foo"), "dirty")
+		},
+		"untracked": func(t *testing.T, top string) {
+			gitfixture.WriteFile(t, filepath.Join(top, "sub", "bar"), "baa")
+		},
+		"nested-modified": func(t *testing.T, top string) {
+			gitfixture.WriteFile(t, filepath.Join(top, "sub", "nested", "foo"), "dirty")
+		},
+		"new-commit": func(t *testing.T, top string) {
+			sub := filepath.Join(top, "sub")
+			gitfixture.RunGit(t, sub, "commit", "-q", "--allow-empty", "-m", "next")
+		},
+	}
+
+	tests := []struct {
+		policy   gitrepo.SubmodulePolicy
+		config   string
+		scenario string
+		want     []string
+	}{
+		{policy: gitrepo.SubmodulesFullRecursive, scenario: "clean"},
+		{policy: gitrepo.SubmodulesFullRecursive, scenario: "modified", want: []string{"sub/foo"}},
+		{policy: gitrepo.SubmodulesFullRecursive, scenario: "untracked", want: []string{"sub/bar"}},
+		{policy: gitrepo.SubmodulesFullRecursive, scenario: "nested-modified", want: []string{"sub/nested/foo"}},
+		{policy: gitrepo.SubmodulesFullRecursive, scenario: "new-commit", want: []string{"sub"}},
+		{policy: gitrepo.SubmodulesCommitOnly, scenario: "modified"},
+		{policy: gitrepo.SubmodulesCommitOnly, scenario: "untracked"},
+		{policy: gitrepo.SubmodulesCommitOnly, scenario: "new-commit", want: []string{"sub"}},
+		{policy: gitrepo.SubmodulesIgnore, scenario: "modified"},
+		{policy: gitrepo.SubmodulesIgnore, scenario: "new-commit"},
+
+		// diff.ignoreSubmodules is followed unless overridden, and
+		// git's default is to consider everything.
+		{scenario: "modified", want: []string{"sub/foo"}},
+		{config: "dirty", scenario: "modified"},
+		{config: "dirty", scenario: "new-commit", want: []string{"sub"}},
+		{config: "all", scenario: "new-commit"},
+		{policy: gitrepo.SubmodulesFullRecursive, config: "all", scenario: "new-commit", want: []string{"sub"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy.String()+"/"+tt.config+"/"+tt.scenario, func(t *testing.T) {
+			// Arrange
+			top := newSuperproject(t)
+			if tt.config != "" {
+				gitfixture.RunGit(t, top, "config", "diff.ignoreSubmodules", tt.config)
+			}
+			scenarios[tt.scenario](t, top)
+
+			cx := openWorktree(t, top)
+			cx.SetSubmodulePolicy(tt.policy)
+
+			// Act
+			entries, doneFn := gitrepo.IterDirtyEntries(t.Context(), cx)
+
+			var got []string
+			for e := range entries {
+				got = append(got, e.Path())
+			}
+			require.NoError(t, doneFn())
+
+			// Assert
+			slices.Sort(got)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLoadSubmodules(t *testing.T) {
+	_ = gitfixture.NewHomeFixture(t)
+	gitfixture.IsolateGitConfig(t)
+
+	// Arrange
+	cx := openWorktree(t, newSuperproject(t))
+
+	// Act
+	submodules, err := gitrepo.LoadSubmodules(cx)
+	require.NoError(t, err)
+
+	// Assert
+	var paths []string
+	for _, sub := range submodules {
+		paths = append(paths, sub.Path)
+	}
+	assert.Equal(t, []string{"sub", "sub/nested"}, paths)
+
+	guide, err := gitrepo.BuildGuide(t.Context(), submodules[0].Context, gitfixture.Head(t, submodules[0].Context), gitrepo.RootScope())
+	require.NoError(t, err)
+	require.NotNil(t, guide.HighestVersion())
+	assert.Equal(t, "v1.0.0", guide.HighestVersion().TagName)
+}
+
+func TestParseSubmodulePolicy(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    gitrepo.SubmodulePolicy
+		wantErr bool
+	}{
+		{in: "", want: gitrepo.SubmodulesConfigured},
+		{in: "ignore", want: gitrepo.SubmodulesIgnore},
+		{in: "all", want: gitrepo.SubmodulesIgnore},
+		{in: "commit-only", want: gitrepo.SubmodulesCommitOnly},
+		{in: "dirty", want: gitrepo.SubmodulesCommitOnly},
+		{in: "full-recursive", want: gitrepo.SubmodulesFullRecursive},
+		{in: "None", want: gitrepo.SubmodulesFullRecursive},
+		{in: "sometimes", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := gitrepo.ParseSubmodulePolicy(tt.in)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -18,6 +18,7 @@ package gitrepo
 
 import (
 	"cmp"
+	"context"
 	"errors"
 	"fmt"
 	"iter"
@@ -164,8 +165,22 @@ func resolveCommitTag(repo *git.Repository, ref *plumbing.Reference) (*CommitTag
 }
 
 // IterCommitTags returns an iterator over all tags resolving to a commit in the repository.
-func IterCommitTags(cx *Context) (iter.Seq[CommitTag], func() error) {
+//
+// Peeling every tag is the most expensive part of collecting tags, so
+// the result is cached under .git/semverkzeug/ and reused for as long
+// as the refs of the repository are unchanged.
+func IterCommitTags(ctx context.Context, cx *Context) (iter.Seq[CommitTag], func() error) {
 	return xit.Perform(func(yield func(CommitTag) bool) error {
+		cache := loadRefCache(cx)
+		if tags, ok := cache.commitTags(); ok {
+			for _, tag := range tags {
+				if !yield(tag) {
+					return nil
+				}
+			}
+			return nil
+		}
+
 		r := cx.Repository()
 
 		tagIter, err := r.Tags()
@@ -173,14 +188,29 @@ func IterCommitTags(cx *Context) (iter.Seq[CommitTag], func() error) {
 			return fmt.Errorf("list tags: %w", err)
 		}
 
+		var resolved []CommitTag
+		var promised []*plumbing.Reference
+		var missing []plumbing.Hash
+		stopped := false
 		walkerFn := func(ref *plumbing.Reference) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
 			switch tag, err := resolveCommitTag(r, ref); {
 			case err != nil:
 				return fmt.Errorf("resolve tag %q: %w", ref.Name().Short(), err)
 			case tag != nil:
+				resolved = append(resolved, *tag)
 				if !yield(*tag) {
+					stopped = true
 					return storer.ErrStop
 				}
+			default:
+				if h, ok := missingTagObject(r, ref); ok {
+					promised = append(promised, ref)
+					missing = append(missing, h)
+				}
 			}
 
 			return nil
@@ -189,6 +219,35 @@ func IterCommitTags(cx *Context) (iter.Seq[CommitTag], func() error) {
 		if err := tagIter.ForEach(walkerFn); err != nil {
 			return fmt.Errorf("iterate tags: %w", err)
 		}
+		if stopped {
+			return nil
+		}
+
+		// Tags whose objects are missing, as in a partial clone, are
+		// fetched from the promisor remote like git does on demand.
+		// Tags that still can't be resolved are skipped, and not
+		// fetched again for a while unless the refs change.
+		var unresolved []string
+		if len(promised) > 0 {
+			var tags []CommitTag
+			tags, unresolved = resolvePromisedTags(ctx, cx, promised, missing)
+			for _, tag := range tags {
+				resolved = append(resolved, tag)
+				if !yield(tag) {
+					return nil
+				}
+			}
+		}
+
+		// A canceled fetch leaves the promised tags unresolved.
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if cache != nil {
+			cache.putCommitTags(resolved, unresolved)
+			_ = cache.save()
+		}
 
 		return nil
 	})
@@ -200,6 +259,11 @@ type VersionTag struct {
 
 	// VersionSpec is the extracted version from the tag.
 	VersionSpec VersionSpec
+
+	// Coerced reports whether the tag name is not a valid version tag
+	// and VersionSpec was coerced from it.  Only set when coercion is
+	// enabled, see Context.SetCoerceTags.
+	Coerced bool
 }
 
 func (vt VersionTag) String() string {
@@ -226,28 +290,44 @@ func (vt VersionTag) CompareDesc(b VersionTag) int {
 
 // FilterMapVersionTags returns an iterator that yields VersionTag objects.
 func FilterMapVersionTags(seq iter.Seq[CommitTag]) iter.Seq[VersionTag] {
+	return filterMapVersionTags(seq, SemVer, false)
+}
+
+// filterMapVersionTags is FilterMapVersionTags for the version tags of
+// scheme, that also coerces tags that are almost semantic versions
+// when coerce is set and scheme is SemVer.
+func filterMapVersionTags(seq iter.Seq[CommitTag], scheme Scheme, coerce bool) iter.Seq[VersionTag] {
 	return xit.FilterMap2(seq, func(t CommitTag) (VersionTag, bool) {
-		vs, err := ParseVersionSpec(t.TagName)
-		if err != nil {
-			return VersionTag{}, false
-		}
+		vt, err := parseVersionTag(t, scheme, coerce)
+		return vt, err == nil
+	})
+}
 
-		vt := VersionTag{
-			CommitTag:   t,
-			VersionSpec: vs,
-		}
+// parseVersionTag parses the name of t as a version tag of scheme,
+// coercing near versions when coerce is set and scheme is SemVer.
+func parseVersionTag(t CommitTag, scheme Scheme, coerce bool) (VersionTag, error) {
+	var vs VersionSpec
+	var coerced bool
+	var err error
+	if coerce && scheme == SemVer {
+		vs, coerced, err = ParseVersionSpecTolerant(t.TagName)
+	} else {
+		vs, err = scheme.ParseVersionSpec(t.TagName)
+	}
+	if err != nil {
+		return VersionTag{}, err
+	}
 
-		return vt, true
-	})
+	return VersionTag{CommitTag: t, VersionSpec: vs, Coerced: coerced}, nil
 }
 
 // IterVersionTags returns an iterator over all version tags in the repository.
-func IterVersionTags(cx *Context, scope *Scope) (iter.Seq[VersionTag], func() error) {
+func IterVersionTags(ctx context.Context, cx *Context, scope *Scope) (iter.Seq[VersionTag], func() error) {
 	// Iterate over all tags resolving to a commit.
-	taggedCommits, doneFn := IterCommitTags(cx)
+	taggedCommits, doneFn := IterCommitTags(ctx, cx)
 
 	// Map tagged commits to version tags.
-	versionTags := FilterMapVersionTags(taggedCommits)
+	versionTags := filterMapVersionTags(taggedCommits, cx.Scheme(), cx.coerceTags)
 
 	// Filter version tags by scope if given.
 	if scope != nil {
@@ -280,8 +360,8 @@ func CollectVersionTagMap(seq iter.Seq[VersionTag]) (out VersionTagMap) {
 
 // NewVersionTagMapFromRepo returns a map of git plumbing.Hash pointing to one or
 // more annotated and unannotated tag names.
-func NewVersionTagMapFromRepo(cx *Context, scope *Scope) (out VersionTagMap, err error) {
-	versionTagsIter, doneFn := IterVersionTags(cx, scope)
+func NewVersionTagMapFromRepo(ctx context.Context, cx *Context, scope *Scope) (out VersionTagMap, err error) {
+	versionTagsIter, doneFn := IterVersionTags(ctx, cx, scope)
 
 	// Collect version tags into a map.
 	out = CollectVersionTagMap(versionTagsIter)
@@ -17,6 +17,7 @@
 package gitrepo_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/go-git/go-git/v5"
@@ -50,7 +51,7 @@ func TestGetTagMap(t *testing.T) {
 		gitfixture.CommitFile(t, repo, "readme.txt", "hello")
 
 		// Act
-		tm, err := gitrepo.NewVersionTagMapFromRepo(repo, nil)
+		tm, err := gitrepo.NewVersionTagMapFromRepo(t.Context(), repo, nil)
 
 		// Assert
 		require.NoError(t, err)
@@ -65,7 +66,7 @@ func TestGetTagMap(t *testing.T) {
 		require.NoError(t, err)
 
 		// Act
-		tm, err := gitrepo.NewVersionTagMapFromRepo(repo, nil)
+		tm, err := gitrepo.NewVersionTagMapFromRepo(t.Context(), repo, nil)
 
 		// Assert
 		require.NoError(t, err)
@@ -83,7 +84,7 @@ func TestGetTagMap(t *testing.T) {
 		require.NoError(t, err)
 
 		// Act
-		tm, err := gitrepo.NewVersionTagMapFromRepo(repo, nil)
+		tm, err := gitrepo.NewVersionTagMapFromRepo(t.Context(), repo, nil)
 
 		// Assert
 		require.NoError(t, err)
@@ -100,7 +101,7 @@ func TestGetTagMap(t *testing.T) {
 		require.NoError(t, err)
 
 		// Act
-		tm, err := gitrepo.NewVersionTagMapFromRepo(repo, nil)
+		tm, err := gitrepo.NewVersionTagMapFromRepo(t.Context(), repo, nil)
 
 		// Assert
 		require.NoError(t, err)
@@ -128,7 +129,7 @@ func TestGetTagMap(t *testing.T) {
 		require.NoError(t, err)
 
 		// Act
-		tm, err := gitrepo.NewVersionTagMapFromRepo(repo, nil)
+		tm, err := gitrepo.NewVersionTagMapFromRepo(t.Context(), repo, nil)
 
 		// Assert: both tags resolve to the same underlying commit; the outer
 		// tag only does so via the recursive peeling branch.
@@ -154,11 +155,28 @@ func TestGetTagMap(t *testing.T) {
 		require.NoError(t, err)
 
 		// Act
-		tm, err := gitrepo.NewVersionTagMapFromRepo(repo, nil)
+		tm, err := gitrepo.NewVersionTagMapFromRepo(t.Context(), repo, nil)
 
 		// Assert
 		require.NoError(t, err)
 		assert.Equal(t, []string{"v1.0.0"}, filterNamesInVersionTagMap(tm, c1.Hash))
 		assert.Equal(t, []string{"v2.0.0"}, filterNamesInVersionTagMap(tm, c2.Hash))
 	})
+	t.Run("canceled", func(t *testing.T) {
+		// Arrange
+		repo := gitfixture.RepoEmpty(t)
+		c := gitfixture.CommitFile(t, repo, "a.txt", "a")
+		_, err := repo.Repository().CreateTag("v1.0.0", c.Hash, nil)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+
+		// Act
+		tm, err := gitrepo.NewVersionTagMapFromRepo(ctx, repo, nil)
+
+		// Assert
+		require.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, tm)
+	})
 }
@@ -24,12 +24,18 @@ var initialVersion = func() VersionSpec {
 	return vs
 }()
 
+// LatestSpec returns the version of the highest tag of g, or the
+// initial version of g's scheme if there is none.
 func LatestSpec(g *Guide) VersionSpec {
 	vt := g.HighestVersion()
 	if vt == nil {
 		// Return the initial version if there are no tags with
 		// the given scope applied.
-		return initialVersion.WithScope(g.Scope)
+		scheme := g.Scheme
+		if scheme == nil {
+			scheme = SemVer
+		}
+		return scheme.initialVersion().WithScope(g.Scope)
 	}
 
 	return vt.VersionSpec
@@ -19,6 +19,7 @@ package gitrepo
 import (
 	"fmt"
 	"regexp"
+	"strings"
 
 	"github.com/Masterminds/semver/v3"
 )
@@ -67,21 +68,37 @@ const (
 		`)`
 
 	scopedSemVerPattern = scopePart + versionPart
+
+	// nearSemVerPattern is what ParseVersionSpecTolerant accepts: one
+	// or more dot-separated numbers, possibly with leading zeros,
+	// followed by anything semver.NewVersion understands.
+	nearSemVerPattern = scopePart +
+		`(?P<prefix>v)?` +
+		`(?P<numbers>\d+(?:\.\d+)*)` +
+		`(?P<rest>[-+].*)?`
 )
 
 var (
 	scopeRegExp  = regexp.MustCompile(`^` + scopePattern + `$`)
 	semVerRegExp = regexp.MustCompile(`^` + scopedSemVerPattern + `$`)
+
+	nearSemVerRegExp = regexp.MustCompile(`^` + nearSemVerPattern + `$`)
 )
 
 func parse(s string) map[string]string {
-	m := semVerRegExp.FindStringSubmatch(s)
+	return match(semVerRegExp, s)
+}
+
+// match returns the named groups of re in s, or nil if re doesn't
+// match.
+func match(re *regexp.Regexp, s string) map[string]string {
+	m := re.FindStringSubmatch(s)
 	if m == nil {
 		return nil
 	}
 
 	out := make(map[string]string, len(m))
-	for i, name := range semVerRegExp.SubexpNames() {
+	for i, name := range re.SubexpNames() {
 		if i == 0 || name == "" {
 			continue
 		}
@@ -100,12 +117,18 @@ type VersionSpec struct {
 
 	// Version is the semver itself, excluding optional scope and excluding optional "v".
 	Version semver.Version
+
+	// scheme formats Version; nil for SemVer.
+	scheme Scheme
 }
 
 func (s VersionSpec) String() (v string) {
 	if !s.Scope.IsRoot() {
 		v = s.Scope.String() + "/"
 	}
+	if s.scheme != nil {
+		return v + s.Prefix + s.scheme.formatVersion(s.Version)
+	}
 	return v + s.Prefix + s.Version.String()
 }
 
@@ -114,6 +137,7 @@ func (s VersionSpec) WithScope(scope Scope) VersionSpec {
 		Scope:   scope,
 		Prefix:  s.Prefix,
 		Version: s.Version,
+		scheme:  s.scheme,
 	}
 }
 
@@ -122,6 +146,7 @@ func (s VersionSpec) WithPrefix(prefix string) VersionSpec {
 		Scope:   s.Scope,
 		Prefix:  prefix,
 		Version: s.Version,
+		scheme:  s.scheme,
 	}
 }
 
@@ -130,6 +155,7 @@ func (s VersionSpec) WithVersion(v semver.Version) VersionSpec {
 		Scope:   s.Scope,
 		Prefix:  s.Prefix,
 		Version: v,
+		scheme:  s.scheme,
 	}
 }
 
@@ -158,3 +184,51 @@ func ParseVersionSpec(original string) (VersionSpec, error) {
 
 	return vt, nil
 }
+
+// ParseVersionSpecTolerant parses a version tag string like
+// ParseVersionSpec, but also coerces tags that are almost semantic
+// versions: missing components are zero ("v1.2" is v1.2.0, "v1" is
+// v1.0.0), leading zeros are dropped ("01.2.3" is 1.2.3), and
+// components beyond the patch version become build metadata
+// ("1.2.3.4" is 1.2.3+4).  coerced reports whether the tag was not a
+// valid version tag as is.
+func ParseVersionSpecTolerant(original string) (vs VersionSpec, coerced bool, err error) {
+	if vs, err := ParseVersionSpec(original); err == nil {
+		return vs, false, nil
+	}
+
+	m := match(nearSemVerRegExp, original)
+	if m == nil {
+		return VersionSpec{}, false, fmt.Errorf("%#q: invalid version tag format", original)
+	}
+
+	s, err := ParseScope(m["scope"])
+	if err != nil {
+		return VersionSpec{}, false, fmt.Errorf("%#q: invalid scope format", original)
+	}
+
+	numbers := strings.Split(m["numbers"], ".")
+	rest := m["rest"]
+	if len(numbers) > 3 {
+		extra := strings.Join(numbers[3:], ".")
+		if pre, metadata, ok := strings.Cut(rest, "+"); ok {
+			rest = pre + "+" + extra + "." + metadata
+		} else {
+			rest += "+" + extra
+		}
+		numbers = numbers[:3]
+	}
+
+	v, err := semver.NewVersion(strings.Join(numbers, ".") + rest)
+	if err != nil {
+		return VersionSpec{}, false, fmt.Errorf("%#q: invalid version tag format", original)
+	}
+
+	vt := VersionSpec{
+		Scope:   s,
+		Prefix:  m["prefix"],
+		Version: *v,
+	}
+
+	return vt, true, nil
+}
@@ -112,3 +112,45 @@ func TestParseVersionTag(t *testing.T) {
 		})
 	}
 }
+
+func TestParseVersionSpecTolerant(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		coerced bool
+		wantErr bool
+	}{
+		{input: "v1.2.3", want: "v1.2.3"},
+		{input: "mod/1.2.3-rc.1", want: "mod/1.2.3-rc.1"},
+		{input: "v1.2", want: "v1.2.0", coerced: true},
+		{input: "v1", want: "v1.0.0", coerced: true},
+		{input: "01.02", want: "1.2.0", coerced: true},
+		{input: "mod/v1.2-rc.1", want: "mod/v1.2.0-rc.1", coerced: true},
+		{input: "1.2.3.4", want: "1.2.3+4", coerced: true},
+		{input: "1.2.3.4.5+build", want: "1.2.3+4.5.build", coerced: true},
+		{input: "1.2.3.4-rc.1", want: "1.2.3-rc.1+4", coerced: true},
+		{input: "release-1.2", wantErr: true},
+		{input: "v1.2.", wantErr: true},
+		{input: "nightly", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			// Act
+			got, coerced, err := ParseVersionSpecTolerant(tt.input)
+
+			// Assert
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.String())
+			assert.Equal(t, tt.coerced, coerced)
+
+			_, err = ParseVersionSpec(got.String())
+			assert.NoError(t, err, "coerced version must be a valid version tag")
+		})
+	}
+}
@@ -0,0 +1,174 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package gitrepo_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0x5a17ed/semverkzeug/internal/gitfixture"
+	"github.com/0x5a17ed/semverkzeug/internal/gitrepo"
+)
+
+// newLinkedWorktrees creates a repository tagged v0.1.0 with the
+// linked worktrees "one" and "two" next to it.  Returns the path of
+// the main worktree.
+func newLinkedWorktrees(t *testing.T) string {
+	t.Helper()
+
+	base, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+
+	main := filepath.Join(base, "main")
+	require.NoError(t, os.Mkdir(main, 0o755))
+	gitfixture.RunGit(t, main, "init", "-q", "-b", "main")
+	gitfixture.WriteFile(t, filepath.Join(main, "foo"), "baa")
+	gitfixture.RunGit(t, main, "add", ".")
+	gitfixture.RunGit(t, main, "commit", "-q", "-m", "initial")
+	gitfixture.RunGit(t, main, "tag", "-a", "-m", "v0.1.0", "v0.1.0")
+
+	for _, name := range []string{"one", "two"} {
+		gitfixture.RunGit(t, main, "worktree", "add", "-q", "-b", name, filepath.Join(base, name))
+	}
+	return main
+}
+
+func openWorktree(t *testing.T, p string) *gitrepo.Context {
+	t.Helper()
+
+	cx, err := gitrepo.NewContextFromPath(p)
+	require.NoError(t, err)
+	return cx
+}
+
+func TestContext_LinkedWorktree(t *testing.T) {
+	_ = gitfixture.NewHomeFixture(t)
+	gitfixture.IsolateGitConfig(t)
+
+	// Arrange
+	main := newLinkedWorktrees(t)
+	two := filepath.Join(filepath.Dir(main), "two")
+	require.NoError(t, os.Mkdir(filepath.Join(two, "sub"), 0o755))
+
+	// Act
+	cx := openWorktree(t, filepath.Join(two, "sub"))
+
+	// Assert
+	root, err := cx.LoadWorktreeRoot()
+	require.NoError(t, err)
+	assert.Equal(t, two, root)
+
+	dotGit, ok := cx.DotGitPath()
+	require.True(t, ok)
+	assert.Equal(t, filepath.Join(main, ".git", "worktrees", "two"), dotGit)
+
+	common, ok := cx.CommonDotGitPath()
+	require.True(t, ok)
+	assert.Equal(t, filepath.Join(main, ".git"), common)
+}
+
+func TestFindStableWorktreeMTime_LinkedWorktrees(t *testing.T) {
+	_ = gitfixture.NewHomeFixture(t)
+	gitfixture.IsolateGitConfig(t)
+
+	// Arrange: Each worktree has a dirty file of its own.
+	main := newLinkedWorktrees(t)
+	base := filepath.Dir(main)
+
+	for _, name := range []string{"one", "two"} {
+		gitfixture.WriteFile(t, filepath.Join(base, name, "foo"), "dirty "+name)
+	}
+
+	// Act
+	got := map[string]time.Time{}
+	for _, name := range []string{"one", "two", "one"} {
+		mtime, err := gitrepo.FindStableWorktreeMTime(t.Context(), openWorktree(t, filepath.Join(base, name)))
+		require.NoError(t, err)
+		if prev, ok := got[name]; ok {
+			// Assert: The other worktree didn't disturb the state.
+			assert.Equal(t, prev, *mtime, name)
+		}
+		got[name] = *mtime
+	}
+
+	// Assert
+	for _, name := range []string{"one", "two"} {
+		assert.FileExists(t, filepath.Join(main, ".git", "worktrees", name, "semverkzeug", "state.json"))
+	}
+	assert.NoFileExists(t, filepath.Join(main, ".git", "semverkzeug", "state.json"))
+}
+
+func TestIterCommitTags_LinkedWorktree(t *testing.T) {
+	_ = gitfixture.NewHomeFixture(t)
+	gitfixture.IsolateGitConfig(t)
+
+	// Arrange: The tags are cached from within a linked worktree.
+	main := newLinkedWorktrees(t)
+	one := filepath.Join(filepath.Dir(main), "one")
+	require.Equal(t, []string{"v0.1.0"}, collectTagNames(t, openWorktree(t, one)))
+
+	// Act: A tag is created from the main worktree.
+	gitfixture.RunGit(t, main, "tag", "v0.2.0")
+	got := collectTagNames(t, openWorktree(t, one))
+
+	// Assert: The shared cache is invalidated by the new tag.
+	assert.Equal(t, []string{"v0.1.0", "v0.2.0"}, got)
+	assert.FileExists(t, filepath.Join(main, ".git", "semverkzeug", "cache.json"))
+}
+
+func TestStatus_WorktreeConfig(t *testing.T) {
+	_ = gitfixture.NewHomeFixture(t)
+	gitfixture.IsolateGitConfig(t)
+
+	// Arrange: Only worktree "one" ignores *.log files.
+	main := newLinkedWorktrees(t)
+	base := filepath.Dir(main)
+
+	gitfixture.RunGit(t, main, "config", "core.repositoryFormatVersion", "1")
+	gitfixture.RunGit(t, main, "config", "extensions.worktreeConfig", "true")
+
+	excludes := filepath.Join(base, "excludes")
+	gitfixture.WriteFile(t, excludes, "*.log\n")
+	gitfixture.RunGit(t, filepath.Join(base, "one"), "config", "--worktree", "core.excludesFile", excludes)
+
+	for _, name := range []string{"one", "two"} {
+		gitfixture.WriteFile(t, filepath.Join(base, name, "build.log"), "baa")
+	}
+
+	tests := []struct {
+		name  string
+		clean bool
+	}{
+		{name: "one", clean: true},
+		{name: "two", clean: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			st, err := gitrepo.BuildWorktreeStatus(t.Context(), openWorktree(t, filepath.Join(base, tt.name)))
+			require.NoError(t, err)
+
+			// Assert
+			assert.Equal(t, tt.clean, st.IsClean(), "status:\n%s", st)
+		})
+	}
+}
@@ -0,0 +1,28 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package stamper
+
+import (
+	"errors"
+)
+
+var (
+	ErrKeyNotFound  = errors.New("version key not found")
+	ErrNotAString   = errors.New("version value is not a string")
+	ErrNoMatch      = errors.New("pattern does not match")
+	ErrFileOutdated = errors.New("file is not up to date")
+)
@@ -0,0 +1,118 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package stamper
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// jsonEditor replaces a string value addressed by a key path of
+// nested objects.
+type jsonEditor struct {
+	keys []string
+}
+
+func (e jsonEditor) Stamp(content []byte, version string) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(content))
+	dec.UseNumber()
+
+	start, end, err := locateJSONValue(dec, content, e.keys)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", strings.Join(e.keys, "."), err)
+	}
+
+	quoted, err := json.Marshal(version)
+	if err != nil {
+		return nil, fmt.Errorf("encode version: %w", err)
+	}
+
+	return splice(content, start, end, string(quoted)), nil
+}
+
+// locateJSONValue returns the byte range of the string value at keys,
+// quotes included.  The decoder must be positioned before an object.
+func locateJSONValue(dec *json.Decoder, content []byte, keys []string) (int, int, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return 0, 0, fmt.Errorf("parse json: %w", err)
+	}
+	if d, ok := tok.(json.Delim); !ok || d != '{' {
+		return 0, 0, ErrKeyNotFound
+	}
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return 0, 0, fmt.Errorf("parse json: %w", err)
+		}
+
+		if tok != keys[0] {
+			if err := skipJSONValue(dec); err != nil {
+				return 0, 0, err
+			}
+			continue
+		}
+
+		if len(keys) > 1 {
+			return locateJSONValue(dec, content, keys[1:])
+		}
+
+		// The offset sits right after the key; the value starts at
+		// the first quote following the colon.
+		after := int(dec.InputOffset())
+		tok, err = dec.Token()
+		if err != nil {
+			return 0, 0, fmt.Errorf("parse json: %w", err)
+		}
+		if _, ok := tok.(string); !ok {
+			return 0, 0, ErrNotAString
+		}
+
+		end := int(dec.InputOffset())
+		start := after + bytes.IndexByte(content[after:end], '"')
+		return start, end, nil
+	}
+
+	return 0, 0, ErrKeyNotFound
+}
+
+// skipJSONValue consumes the next complete value from dec.
+func skipJSONValue(dec *json.Decoder) error {
+	depth := 0
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("parse json: %w", err)
+		}
+
+		if d, ok := tok.(json.Delim); ok {
+			switch d {
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+			}
+		}
+
+		if depth == 0 {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,60 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package stamper
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// VersionPlaceholder marks the version in a template, see
+// CompileTemplate.
+const VersionPlaceholder = "{{version}}"
+
+// CompileTemplate turns a template, text holding VersionPlaceholder
+// once like `APP_VERSION="{{version}}"`, into a pattern for
+// ParseTarget.  The pattern matches the text around the placeholder
+// literally and captures the version, a run of non-space characters,
+// in its place.
+func CompileTemplate(tmpl string) (*regexp.Regexp, error) {
+	before, after, ok := strings.Cut(tmpl, VersionPlaceholder)
+	if !ok || strings.Contains(after, VersionPlaceholder) {
+		return nil, fmt.Errorf("%#q: template must contain %s exactly once", tmpl, VersionPlaceholder)
+	}
+
+	// Stop at the first occurrence of the text following the version.
+	value := `(\S*?)`
+	if after == "" {
+		value = `(\S*)`
+	}
+	return regexp.Compile(regexp.QuoteMeta(before) + value + regexp.QuoteMeta(after))
+}
+
+// regexEditor replaces the first capture group of the first match.
+type regexEditor struct {
+	re *regexp.Regexp
+}
+
+func (e regexEditor) Stamp(content []byte, version string) ([]byte, error) {
+	m := e.re.FindSubmatchIndex(content)
+	if m == nil || m[2] < 0 {
+		return nil, ErrNoMatch
+	}
+
+	return splice(content, m[2], m[3], version), nil
+}
@@ -0,0 +1,148 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+// Package stamper writes a version string into project files such as
+// package.json, Cargo.toml or Chart.yaml.  Every editor rewrites only
+// the bytes of the version value itself, so formatting, comments and
+// key order of the surrounding document are preserved.
+package stamper
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/google/renameio/v2"
+)
+
+// Editor replaces the version value in the content of a file.
+type Editor interface {
+	// Stamp returns content with the version value replaced by
+	// version.  The input slice is never modified.
+	Stamp(content []byte, version string) ([]byte, error)
+}
+
+// Target describes a file and the editor used to stamp it.
+type Target struct {
+	// Path to the file, relative to the current working directory.
+	Path string
+
+	// Editor rewrites the file's version value.
+	Editor Editor
+}
+
+// wellKnownKeys maps file base names to the key holding their version.
+var wellKnownKeys = map[string]string{
+	"package.json":   "version",
+	"composer.json":  "version",
+	"Cargo.toml":     "package.version",
+	"pyproject.toml": "project.version",
+	"Chart.yaml":     "version",
+}
+
+// goVersionPattern matches a `Version = "..."` declaration in Go
+// sources, the conventional target of `-X main.Version=...`.
+var goVersionPattern = regexp.MustCompile(`(?m)^\s*(?:var\s+|const\s+)?Version\s*(?:string\s*)?=\s*"([^"\n]*)"`)
+
+// ParseTarget parses a target specification of the form PATH or
+// PATH:KEY.  The editor is chosen from the file name: JSON, TOML and
+// YAML files use KEY (a dot-separated path, defaulting to the
+// conventional key for well-known manifests), Go sources use a
+// `Version = "..."` pattern, and every other file falls back to
+// pattern, which must contain exactly one capture group.  Patterns are
+// regular expressions or templates compiled by CompileTemplate.
+func ParseTarget(spec string, pattern *regexp.Regexp) (Target, error) {
+	p, key, _ := strings.Cut(spec, ":")
+	if p == "" {
+		return Target{}, fmt.Errorf("%#q: empty path", spec)
+	}
+
+	if key == "" {
+		key = wellKnownKeys[filepath.Base(p)]
+	}
+	if key == "" {
+		key = "version"
+	}
+	keys := strings.Split(key, ".")
+
+	var ed Editor
+	switch ext := strings.ToLower(filepath.Ext(p)); {
+	case ext == ".json":
+		ed = jsonEditor{keys: keys}
+	case ext == ".toml":
+		ed = tomlEditor{keys: keys}
+	case ext == ".yaml" || ext == ".yml":
+		ed = yamlEditor{keys: keys}
+	case pattern != nil:
+		ed = regexEditor{re: pattern}
+	case ext == ".go":
+		ed = regexEditor{re: goVersionPattern}
+	default:
+		return Target{}, fmt.Errorf("%#q: unsupported file type, provide a pattern or template", spec)
+	}
+
+	if re, ok := ed.(regexEditor); ok && re.re.NumSubexp() != 1 {
+		return Target{}, fmt.Errorf("%#q: pattern must contain exactly one capture group", spec)
+	}
+
+	return Target{Path: p, Editor: ed}, nil
+}
+
+// Apply stamps version into the target file.  It reports whether the
+// file content differs from the stamped content.  When check is set
+// the file is left untouched; otherwise changed content is written
+// atomically, keeping the file's permission bits.
+func Apply(t Target, version string, check bool) (changed bool, err error) {
+	fi, err := os.Stat(t.Path)
+	if err != nil {
+		return false, fmt.Errorf("stat file: %w", err)
+	}
+
+	content, err := os.ReadFile(t.Path)
+	if err != nil {
+		return false, fmt.Errorf("read file: %w", err)
+	}
+
+	stamped, err := t.Editor.Stamp(content, version)
+	if err != nil {
+		return false, err
+	}
+
+	if bytes.Equal(content, stamped) {
+		return false, nil
+	}
+	if check {
+		return true, nil
+	}
+
+	if err := renameio.WriteFile(t.Path, stamped, fi.Mode().Perm()); err != nil {
+		return false, fmt.Errorf("write file: %w", err)
+	}
+
+	return true, nil
+}
+
+// splice returns a copy of content with content[start:end] replaced
+// by repl.
+func splice(content []byte, start, end int, repl string) []byte {
+	out := make([]byte, 0, len(content)-(end-start)+len(repl))
+	out = append(out, content[:start]...)
+	out = append(out, repl...)
+	return append(out, content[end:]...)
+}
@@ -0,0 +1,204 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package stamper_test
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0x5a17ed/semverkzeug/internal/gitfixture"
+	"github.com/0x5a17ed/semverkzeug/internal/stamper"
+)
+
+func TestApply(t *testing.T) {
+	type args struct {
+		file    string
+		spec    string
+		content string
+		want    string
+	}
+
+	tests := []struct {
+		name string
+		args args
+	}{
+		{"package-json", args{
+			file:    "package.json",
+			content: "{\n  \"name\": \"x\",\n  \"deps\": {\"version\": \"9\"},\n  \"version\": \"0.0.0\"\n}\n",
+			want:    "{\n  \"name\": \"x\",\n  \"deps\": {\"version\": \"9\"},\n  \"version\": \"1.2.3\"\n}\n",
+		}},
+		{"json-nested-key", args{
+			file:    "manifest.json",
+			spec:    "manifest.json:app.version",
+			content: `{"version": [1], "app": {"version" : "0.1.0"}}`,
+			want:    `{"version": [1], "app": {"version" : "1.2.3"}}`,
+		}},
+		{"cargo-toml", args{
+			file:    "Cargo.toml",
+			content: "version = \"top\"\n\n[package]\nname = \"x\"\nversion = \"0.1.0\" # keep me\n\n[dependencies]\nversion = \"9\"\n",
+			want:    "version = \"top\"\n\n[package]\nname = \"x\"\nversion = \"1.2.3\" # keep me\n\n[dependencies]\nversion = \"9\"\n",
+		}},
+		{"pyproject-toml-dotted", args{
+			file:    "pyproject.toml",
+			content: "project.name = 'x'\nproject.version = '0.1.0'\n",
+			want:    "project.name = 'x'\nproject.version = '1.2.3'\n",
+		}},
+		{"chart-yaml", args{
+			file:    "Chart.yaml",
+			content: "apiVersion: v2\n# comment\nversion: 0.1.0\nappVersion: \"0.1.0\"\n",
+			want:    "apiVersion: v2\n# comment\nversion: 1.2.3\nappVersion: \"0.1.0\"\n",
+		}},
+		{"chart-yaml-quoted", args{
+			file:    "Chart.yaml",
+			spec:    "Chart.yaml:appVersion",
+			content: "version: 0.1.0\nappVersion: \"0.1.0\"\n",
+			want:    "version: 0.1.0\nappVersion: \"1.2.3\"\n",
+		}},
+		{"go-source", args{
+			file:    "version.go",
+			content: "package main\n\nvar Version = \"\"\n",
+			want:    "package main\n\nvar Version = \"1.2.3\"\n",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			p := filepath.Join(t.TempDir(), tt.args.file)
+			gitfixture.WriteFile(t, p, tt.args.content)
+
+			spec := p
+			if tt.args.spec != "" {
+				spec = filepath.Join(filepath.Dir(p), tt.args.spec)
+			}
+			target, err := stamper.ParseTarget(spec, nil)
+			require.NoError(t, err)
+
+			// Act
+			changed, err := stamper.Apply(target, "1.2.3", false)
+
+			// Assert
+			require.NoError(t, err)
+			assert.True(t, changed)
+
+			got, err := os.ReadFile(p)
+			require.NoError(t, err)
+			assert.Equal(t, tt.args.want, string(got))
+		})
+	}
+}
+
+func TestApply_Check(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "package.json")
+	gitfixture.WriteFile(t, p, `{"version": "0.1.0"}`)
+
+	target, err := stamper.ParseTarget(p, nil)
+	require.NoError(t, err)
+
+	changed, err := stamper.Apply(target, "1.2.3", true)
+	require.NoError(t, err)
+	assert.True(t, changed)
+
+	got, err := os.ReadFile(p)
+	require.NoError(t, err)
+	assert.Equal(t, `{"version": "0.1.0"}`, string(got), "check must not modify the file")
+
+	changed, err = stamper.Apply(target, "0.1.0", true)
+	require.NoError(t, err)
+	assert.False(t, changed)
+}
+
+func TestApply_Pattern(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "VERSION.txt")
+	gitfixture.WriteFile(t, p, "release: 0.1.0\n")
+
+	target, err := stamper.ParseTarget(p, regexp.MustCompile(`release: (\S+)`))
+	require.NoError(t, err)
+
+	_, err = stamper.Apply(target, "1.2.3", false)
+	require.NoError(t, err)
+
+	got, err := os.ReadFile(p)
+	require.NoError(t, err)
+	assert.Equal(t, "release: 1.2.3\n", string(got))
+}
+
+func TestApply_Template(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "app.env")
+	gitfixture.WriteFile(t, p, "APP_NAME=x\nAPP_VERSION=\"0.1.0\";OTHER=\"y\"\n")
+
+	pattern, err := stamper.CompileTemplate(`APP_VERSION="{{version}}"`)
+	require.NoError(t, err)
+	target, err := stamper.ParseTarget(p, pattern)
+	require.NoError(t, err)
+
+	_, err = stamper.Apply(target, "1.2.3-rc.1", false)
+	require.NoError(t, err)
+
+	got, err := os.ReadFile(p)
+	require.NoError(t, err)
+	assert.Equal(t, "APP_NAME=x\nAPP_VERSION=\"1.2.3-rc.1\";OTHER=\"y\"\n", string(got))
+}
+
+func TestCompileTemplate(t *testing.T) {
+	tests := []struct {
+		tmpl, content, want string
+		wantErr             bool
+	}{
+		{tmpl: "version: {{version}}", content: "version: 0.1.0 # pinned\n", want: "0.1.0"},
+		{tmpl: "(v{{version}})", content: "app (v0.1.0)\n", want: "0.1.0"},
+		{tmpl: "version", wantErr: true},
+		{tmpl: "{{version}}-{{version}}", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tmpl, func(t *testing.T) {
+			got, err := stamper.CompileTemplate(tt.tmpl)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			m := got.FindStringSubmatch(tt.content)
+			require.Len(t, m, 2)
+			assert.Equal(t, tt.want, m[1])
+		})
+	}
+}
+
+func TestApply_KeyNotFound(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "Chart.yaml")
+	gitfixture.WriteFile(t, p, "name: x\n")
+
+	target, err := stamper.ParseTarget(p, nil)
+	require.NoError(t, err)
+
+	_, err = stamper.Apply(target, "1.2.3", false)
+	assert.ErrorIs(t, err, stamper.ErrKeyNotFound)
+}
+
+func TestParseTarget_Unsupported(t *testing.T) {
+	_, err := stamper.ParseTarget("VERSION", nil)
+	assert.Error(t, err)
+
+	_, err = stamper.ParseTarget("VERSION", regexp.MustCompile(`(a)(b)`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package stamper
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	tomlTableRegExp    = regexp.MustCompile(`^\s*\[\s*([^\[\]]+?)\s*\]\s*(?:#.*)?$`)
+	tomlArrayRegExp    = regexp.MustCompile(`^\s*\[\[`)
+	tomlKeyValueRegExp = regexp.MustCompile(`^\s*([A-Za-z0-9_\-."' ]+?)\s*=\s*(?:"([^"\\\n]*)"|'([^'\n]*)')`)
+)
+
+// tomlEditor replaces a basic or literal string value addressed by a
+// key path.  It works line by line, tracking the current table
+// header, and understands dotted keys both in headers and on the
+// left-hand side of an assignment.  Inline tables, arrays of tables
+// and multi-line strings are not searched.
+type tomlEditor struct {
+	keys []string
+}
+
+func (e tomlEditor) Stamp(content []byte, version string) ([]byte, error) {
+	var table []string
+	inArray := false
+	offset := 0
+
+	for line := range bytes.Lines(content) {
+		lineStart := offset
+		offset += len(line)
+
+		if tomlArrayRegExp.Match(line) {
+			// Arrays of tables are never addressed by a plain key path.
+			inArray = true
+			continue
+		}
+		if m := tomlTableRegExp.FindSubmatch(line); m != nil {
+			table, inArray = splitTOMLKey(string(m[1])), false
+			continue
+		}
+		if inArray {
+			continue
+		}
+
+		m := tomlKeyValueRegExp.FindSubmatchIndex(line)
+		if m == nil {
+			continue
+		}
+
+		key := append(slices.Clone(table), splitTOMLKey(string(line[m[2]:m[3]]))...)
+		if !slices.Equal(key, e.keys) {
+			continue
+		}
+
+		// Group 2 holds basic strings, group 3 literal strings.
+		start, end := m[4], m[5]
+		if start < 0 {
+			start, end = m[6], m[7]
+		}
+		if end+1 < len(line) && (line[end+1] == '"' || line[end+1] == '\'') {
+			return nil, fmt.Errorf("%s: %w", strings.Join(e.keys, "."), ErrNotAString)
+		}
+
+		return splice(content, lineStart+start, lineStart+end, version), nil
+	}
+
+	return nil, fmt.Errorf("%s: %w", strings.Join(e.keys, "."), ErrKeyNotFound)
+}
+
+// splitTOMLKey splits a dotted TOML key into its unquoted parts.
+func splitTOMLKey(s string) []string {
+	parts := strings.Split(s, ".")
+	for i, p := range parts {
+		parts[i] = strings.Trim(strings.TrimSpace(p), `"'`)
+	}
+	return parts
+}
@@ -0,0 +1,152 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package stamper
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yamlEditor replaces a scalar value addressed by a key path of
+// nested mappings, keeping the scalar's quoting style.
+type yamlEditor struct {
+	keys []string
+}
+
+func (e yamlEditor) Stamp(content []byte, version string) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("parse yaml: %w", err)
+	}
+
+	node, err := findYAMLScalar(&doc, e.keys)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", strings.Join(e.keys, "."), err)
+	}
+
+	start, end, err := yamlScalarRange(content, node)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", strings.Join(e.keys, "."), err)
+	}
+
+	var repl string
+	switch node.Style {
+	case yaml.DoubleQuotedStyle:
+		repl = `"` + version + `"`
+	case yaml.SingleQuotedStyle:
+		repl = `'` + version + `'`
+	default:
+		repl = version
+	}
+
+	return splice(content, start, end, repl), nil
+}
+
+// findYAMLScalar walks the mappings along keys and returns the scalar
+// node found at the end of the path.
+func findYAMLScalar(node *yaml.Node, keys []string) (*yaml.Node, error) {
+	if node.Kind == yaml.DocumentNode {
+		if len(node.Content) == 0 {
+			return nil, ErrKeyNotFound
+		}
+		node = node.Content[0]
+	}
+
+	for _, key := range keys {
+		if node.Kind != yaml.MappingNode {
+			return nil, ErrKeyNotFound
+		}
+
+		var next *yaml.Node
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == key {
+				next = node.Content[i+1]
+				break
+			}
+		}
+		if next == nil {
+			return nil, ErrKeyNotFound
+		}
+		node = next
+	}
+
+	if node.Kind != yaml.ScalarNode || node.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+		return nil, ErrNotAString
+	}
+	return node, nil
+}
+
+// yamlScalarRange returns the byte range the scalar node occupies in
+// content, quotes included.
+func yamlScalarRange(content []byte, node *yaml.Node) (int, int, error) {
+	// Locate the start of the node's line.
+	lineStart := 0
+	for range node.Line - 1 {
+		i := bytes.IndexByte(content[lineStart:], '\n')
+		if i < 0 {
+			return 0, 0, ErrKeyNotFound
+		}
+		lineStart += i + 1
+	}
+
+	// Columns count characters, not bytes.
+	start := lineStart
+	for range node.Column - 1 {
+		_, size := utf8.DecodeRune(content[start:])
+		start += size
+	}
+
+	lineEnd := len(content)
+	if i := bytes.IndexByte(content[start:], '\n'); i >= 0 {
+		lineEnd = start + i
+	}
+	rest := content[start:lineEnd]
+
+	switch node.Style {
+	case yaml.DoubleQuotedStyle:
+		for i := 1; i < len(rest); i++ {
+			switch rest[i] {
+			case '\\':
+				i++
+			case '"':
+				return start, start + i + 1, nil
+			}
+		}
+	case yaml.SingleQuotedStyle:
+		for i := 1; i < len(rest); i++ {
+			if rest[i] != '\'' {
+				continue
+			}
+			if i+1 < len(rest) && rest[i+1] == '\'' {
+				i++
+				continue
+			}
+			return start, start + i + 1, nil
+		}
+	default:
+		if bytes.HasPrefix(rest, []byte(node.Value)) {
+			return start, start + len(node.Value), nil
+		}
+	}
+
+	// Multi-line scalars are not supported.
+	return 0, 0, ErrNotAString
+}
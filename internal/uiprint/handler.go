@@ -0,0 +1,152 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package uiprint
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Handler is a slog.Handler writing records in the format of Step,
+// Substep, Hint, Warning and Error.  Records at LevelInfo are steps
+// unless their KindKey attribute says otherwise, and debug records
+// are prefixed with "  .. ".  Other attributes follow the message as
+// key=value pairs.
+type Handler struct {
+	mu    *sync.Mutex
+	w     io.Writer
+	level slog.Leveler
+
+	// fields are the formatted attributes of WithAttrs, and group the
+	// key prefix of WithGroup.
+	fields []string
+	group  string
+}
+
+// NewHandler returns a Handler writing to w.  Only opts.Level is
+// honored; it defaults to LevelInfo.
+func NewHandler(w io.Writer, opts *slog.HandlerOptions) *Handler {
+	h := &Handler{mu: &sync.Mutex{}, w: w, level: slog.LevelInfo}
+	if opts != nil && opts.Level != nil {
+		h.level = opts.Level
+	}
+	return h
+}
+
+func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *Handler) Handle(_ context.Context, r slog.Record) error {
+	var kind string
+	// Clip so concurrent records never append into the spare capacity
+	// of the shared fields.
+	fields := slices.Clip(h.fields)
+	r.Attrs(func(a slog.Attr) bool {
+		if a.Key == KindKey && h.group == "" {
+			kind = a.Value.String()
+			return true
+		}
+		fields = appendField(fields, h.group, a)
+		return true
+	})
+
+	var sb strings.Builder
+	switch {
+	case r.Level >= slog.LevelError:
+		sb.WriteString(errorPrefix.Sprint("==> ERROR: "))
+		sb.WriteString(r.Message)
+	case r.Level >= slog.LevelWarn:
+		sb.WriteString(warningPrefix.Sprint("==> WARNING: "))
+		sb.WriteString(r.Message)
+	case r.Level >= slog.LevelInfo && kind == KindSubstep:
+		sb.WriteString(substepPrefix.Sprint(" -> "))
+		sb.WriteString(r.Message)
+	case r.Level >= slog.LevelInfo && kind == KindHint:
+		sb.WriteString(hintPrefix.Sprint("   :: "))
+		sb.WriteString(r.Message)
+	case r.Level >= slog.LevelInfo:
+		sb.WriteString(stepPrefix.Sprint("==> "))
+		sb.WriteString(boldText.Sprint(r.Message))
+	default:
+		sb.WriteString(debugPrefix.Sprint("  .. "))
+		sb.WriteString(r.Message)
+	}
+	for _, f := range fields {
+		sb.WriteByte(' ')
+		sb.WriteString(f)
+	}
+	sb.WriteByte('\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.w, sb.String())
+	return err
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	h2.fields = nil
+	for _, a := range attrs {
+		h2.fields = appendField(h2.fields, h.group, a)
+	}
+	h2.fields = append(h.fields[:len(h.fields):len(h.fields)], h2.fields...)
+	return &h2
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.group = h.group + name + "."
+	return &h2
+}
+
+// appendField appends a formatted as key=value to fields, with group
+// prefixed to its key; groups are flattened into dotted keys.
+func appendField(fields []string, group string, a slog.Attr) []string {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return fields
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			group += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			fields = appendField(fields, group, ga)
+		}
+		return fields
+	}
+
+	v := a.Value.String()
+	if a.Value.Kind() == slog.KindTime {
+		v = a.Value.Time().Format(time.RFC3339Nano)
+	}
+	if v == "" || strings.ContainsAny(v, " \t\n\"=") {
+		v = strconv.Quote(v)
+	}
+	return append(fields, group+a.Key+"="+v)
+}
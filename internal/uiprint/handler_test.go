@@ -0,0 +1,83 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package uiprint
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = true
+	t.Cleanup(func() { color.NoColor = noColor })
+
+	var buf bytes.Buffer
+	logger := slog.New(NewHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	logger.Info("Building", KindKey, KindStep)
+	logger.Info("Target: v1.0.0", KindKey, KindSubstep)
+	logger.Info("touch your key", KindKey, KindHint)
+	logger.Warn("shallow clone")
+	logger.Error("failed", "err", "no such tag")
+	logger.Debug("skip version tag", "tag", "v9.0.0", "reason", "stranded")
+	logger.With("scope", "app").WithGroup("guide").Debug("select", "depth", 2, slog.Group("base", "tag", "v1.0.0"))
+	logger.Debug("dirty entry", "mtime", time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC), "empty", "")
+
+	assert.Equal(t, `==> Building
+ -> Target: v1.0.0
+   :: touch your key
+==> WARNING: shallow clone
+==> ERROR: failed err="no such tag"
+  .. skip version tag tag=v9.0.0 reason=stranded
+  .. select scope=app guide.depth=2 guide.base.tag=v1.0.0
+  .. dirty entry mtime=2026-10-19T12:00:00Z empty=""
+`, buf.String())
+}
+
+func TestHandler_Level(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewHandler(&buf, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	logger.Info("Building", KindKey, KindStep)
+	logger.Warn("shallow clone")
+	logger.Debug("skip version tag")
+	assert.Empty(t, buf.String())
+}
+
+func TestHandler_HandleKeepsSharedFields(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = true
+	t.Cleanup(func() { color.NoColor = noColor })
+
+	var buf bytes.Buffer
+	h := NewHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	h.fields = make([]string, 1, 4)
+	h.fields[0] = "scope=app"
+
+	r := slog.NewRecord(time.Time{}, slog.LevelDebug, "select", 0)
+	r.AddAttrs(slog.Int("depth", 2))
+	assert.NoError(t, h.Handle(t.Context(), r))
+
+	assert.Equal(t, "  .. select scope=app depth=2\n", buf.String())
+	assert.Empty(t, h.fields[:2][1], "records must not write into the fields of the handler")
+}
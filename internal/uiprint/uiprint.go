@@ -20,11 +20,19 @@
 //	==>   top-level step
 //	 ->   sub-step
 //	   :: hint or aside
+//
+// Messages are log/slog records at LevelInfo, or LevelWarn and
+// LevelError for warnings and errors.  Configure sets the level and
+// whether they are written in this format or as JSON lines, and makes
+// the same handler the default logger, so debug events of other
+// packages end up in the same stream.
 package uiprint
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
 
 	"github.com/fatih/color"
@@ -35,12 +43,16 @@ var (
 	stepPrefix    = color.New(color.FgGreen, color.Bold)
 	substepPrefix = color.New(color.FgBlue, color.Bold)
 	hintPrefix    = color.New(color.FgYellow, color.Bold)
+	warningPrefix = color.New(color.FgYellow, color.Bold)
 	errorPrefix   = color.New(color.FgRed, color.Bold)
+	debugPrefix   = color.New(color.FgCyan)
 	boldText      = color.New(color.Bold)
 
 	// out is the destination for all messages.  Stderr keeps stdout
 	// clean for callers that pipe the tool's data output.
 	out io.Writer = os.Stderr
+
+	logger = slog.New(NewHandler(out, nil))
 )
 
 func init() {
@@ -51,26 +63,75 @@ func init() {
 	}
 }
 
+// Format selects how messages are written.
+type Format int
+
+const (
+	// FormatText writes messages with the prefixes above.
+	FormatText Format = iota
+
+	// FormatJSON writes every message as a JSON object on its own
+	// line, see slog.JSONHandler.
+	FormatJSON
+)
+
+// Configure writes the messages of at least level in format from now
+// on, and makes that the default slog logger.
+func Configure(level slog.Leveler, format Format) {
+	opts := &slog.HandlerOptions{Level: level}
+
+	var h slog.Handler = NewHandler(out, opts)
+	if format == FormatJSON {
+		h = slog.NewJSONHandler(out, opts)
+	}
+	logger = slog.New(h)
+	slog.SetDefault(logger)
+}
+
+// KindKey is the attribute telling steps, sub-steps and hints apart.
+const KindKey = "ui"
+
+// Kinds of messages at LevelInfo.
+const (
+	KindStep    = "step"
+	KindSubstep = "substep"
+	KindHint    = "hint"
+)
+
+func emit(level slog.Level, kind string, format string, args []any) {
+	ctx := context.Background()
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+
+	var attrs []any
+	if kind != "" {
+		attrs = append(attrs, slog.String(KindKey, kind))
+	}
+	logger.Log(ctx, level, fmt.Sprintf(format, args...), attrs...)
+}
+
 // Step prints a top-level step line.
 func Step(format string, args ...any) {
-	_, _ = stepPrefix.Fprint(out, "==> ")
-	_, _ = boldText.Fprintln(out, fmt.Sprintf(format, args...))
+	emit(slog.LevelInfo, KindStep, format, args)
 }
 
 // Substep prints a sub-step line.
 func Substep(format string, args ...any) {
-	_, _ = substepPrefix.Fprint(out, " -> ")
-	_, _ = fmt.Fprintln(out, fmt.Sprintf(format, args...))
+	emit(slog.LevelInfo, KindSubstep, format, args)
 }
 
 // Hint prints a parenthetical aside.
 func Hint(format string, args ...any) {
-	_, _ = hintPrefix.Fprint(out, "   :: ")
-	_, _ = fmt.Fprintln(out, fmt.Sprintf(format, args...))
+	emit(slog.LevelInfo, KindHint, format, args)
+}
+
+// Warning prints a pacman/makepkg-style warning line.
+func Warning(format string, args ...any) {
+	emit(slog.LevelWarn, "", format, args)
 }
 
 // Error prints a pacman/makepkg-style error line.
 func Error(format string, args ...any) {
-	_, _ = errorPrefix.Fprint(out, "==> ERROR: ")
-	_, _ = fmt.Fprintln(out, fmt.Sprintf(format, args...))
+	emit(slog.LevelError, "", format, args)
 }
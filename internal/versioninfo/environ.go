@@ -0,0 +1,60 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package versioninfo
+
+import (
+	"strconv"
+	"time"
+)
+
+// EnvPrefix is the common prefix of all environment variables
+// produced by Environ.
+const EnvPrefix = "SEMVER"
+
+// Environ returns info as a list of "KEY=value" environment variable
+// assignments.  Every variable is always present so child processes
+// can distinguish "empty" from "not run under semverkzeug".
+func (info *Info) Environ() []string {
+	var commitDate string
+	if !info.CommitDate.IsZero() {
+		commitDate = info.CommitDate.UTC().Format(time.RFC3339)
+	}
+
+	vars := []struct{ key, value string }{
+		{"", info.SemVer},
+		{"_VERSION", info.Version},
+		{"_SCOPE", info.Scope},
+		{"_PREFIX", info.Prefix},
+		{"_MAJOR", strconv.FormatUint(info.Major, 10)},
+		{"_MINOR", strconv.FormatUint(info.Minor, 10)},
+		{"_PATCH", strconv.FormatUint(info.Patch, 10)},
+		{"_PRERELEASE", info.Prerelease},
+		{"_METADATA", info.Metadata},
+		{"_COMMIT", info.Commit},
+		{"_SHORT_COMMIT", info.ShortCommit},
+		{"_COMMIT_DATE", commitDate},
+		{"_BASE_TAG", info.BaseTag},
+		{"_DEPTH", strconv.Itoa(info.Depth)},
+		{"_DIRTY", strconv.FormatBool(info.Dirty)},
+	}
+
+	out := make([]string, 0, len(vars))
+	for _, v := range vars {
+		out = append(out, EnvPrefix+v.key+"="+v.value)
+	}
+	return out
+}
@@ -0,0 +1,83 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package versioninfo
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/google/renameio/v2"
+)
+
+// templateFuncs are the helper functions available to templates in
+// addition to the text/template builtins.
+var templateFuncs = template.FuncMap{
+	"join": strings.Join,
+}
+
+// Render executes the text/template in text with info as its data.
+// Referencing a field Info doesn't have fails the execution.
+func Render(name, text string, info *Info) ([]byte, error) {
+	tmpl, err := template.New(name).
+		Funcs(templateFuncs).
+		Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parse template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, info); err != nil {
+		return nil, fmt.Errorf("execute template: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// WriteFileIfChanged atomically replaces the file at path with data,
+// unless the file already holds exactly data.  Leaving unchanged
+// files alone keeps their mtime, so build systems don't rebuild
+// needlessly.  An existing file keeps its permissions.  It reports
+// whether the file was written.
+func WriteFileIfChanged(path string, data []byte) (bool, error) {
+	perm := fs.FileMode(0o644)
+	current, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		// Fall through.
+	case err != nil:
+		return false, fmt.Errorf("read file: %w", err)
+	case bytes.Equal(current, data):
+		return false, nil
+	default:
+		fi, err := os.Stat(path)
+		if err != nil {
+			return false, fmt.Errorf("stat file: %w", err)
+		}
+		perm = fi.Mode().Perm()
+	}
+
+	if err := renameio.WriteFile(path, data, perm); err != nil {
+		return false, fmt.Errorf("write file: %w", err)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,187 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+// Package versioninfo gathers everything known about a described
+// version into a single flat value suitable for templates and
+// environment variables.
+package versioninfo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+
+	"github.com/0x5a17ed/semverkzeug/internal/floatingversion"
+	"github.com/0x5a17ed/semverkzeug/internal/gitrepo"
+)
+
+// Options control how Collect derives the version.
+type Options struct {
+	// AddCommitHash adds the abbreviated commit hash as build metadata.
+	AddCommitHash bool
+
+	// Explain records how the version was derived in Info.Explanation.
+	// It bypasses the guide cache.
+	Explain bool
+}
+
+// Explanation is how Collect derived a version.
+type Explanation struct {
+	// Guide is the guide the version derives from, and Candidates the
+	// version tags considered for it, see gitrepo.ExplainGuide.
+	Guide      *gitrepo.Guide
+	Candidates []gitrepo.TagCandidate
+
+	// Resolution is how the floating version was resolved from Guide.
+	Resolution *floatingversion.Explanation
+}
+
+// Info is the flattened result of describing a reference.
+type Info struct {
+	// Spec is the described version.
+	Spec gitrepo.VersionSpec
+
+	// Version is the full version string including scope and prefix,
+	// exactly as `describe` prints it.
+	Version string
+
+	// SemVer is the version without scope and prefix.
+	SemVer string
+
+	Scope  string
+	Prefix string
+
+	Major uint64
+	Minor uint64
+	Patch uint64
+
+	// Prerelease is the dot-separated prerelease label and
+	// PrereleaseIdentifiers its individual identifiers.
+	Prerelease            string
+	PrereleaseIdentifiers []string
+
+	// Metadata is the build metadata label.
+	Metadata string
+
+	// Commit is the full hash of the described commit and ShortCommit
+	// its shortest unique abbreviation.  Both are empty for an empty
+	// repository.
+	Commit      string
+	ShortCommit string
+
+	// CommitDate is the committer date of the described commit.
+	CommitDate time.Time
+
+	// BaseTag is the name of the version tag the version derives
+	// from, empty when no tag is reachable.
+	BaseTag string
+
+	// Depth is the number of commits since BaseTag's branch point.
+	Depth int
+
+	// Dirty reports whether the worktree contains uncommitted changes.
+	Dirty bool
+
+	// Explanation is set when Options.Explain is.
+	Explanation *Explanation
+}
+
+// Collect describes ref within scope and returns the flattened result.
+func Collect(
+	ctx context.Context,
+	cx *gitrepo.Context,
+	ref *plumbing.Reference,
+	scope gitrepo.Scope,
+	opts Options,
+) (*Info, error) {
+	var guide *gitrepo.Guide
+	var explanation *Explanation
+	var err error
+	if opts.Explain {
+		explanation = &Explanation{}
+		guide, explanation.Candidates, err = gitrepo.ExplainGuide(ctx, cx, ref, scope)
+		explanation.Guide = guide
+	} else {
+		guide, err = gitrepo.BuildGuide(ctx, cx, ref, scope)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("build guide: %w", err)
+	}
+
+	resolution, err := floatingversion.Explain(ctx, cx, guide)
+	if err != nil {
+		return nil, err
+	}
+	res := resolution.Result
+	if explanation != nil {
+		explanation.Resolution = resolution
+	}
+
+	info := &Info{
+		Depth:       guide.Depth,
+		Dirty:       res.Dirty,
+		Explanation: explanation,
+	}
+
+	if vt := guide.HighestVersion(); vt != nil {
+		info.BaseTag = vt.TagName
+	}
+
+	spec := res.Spec
+	if guide.HasCommit() {
+		abbreviatedHash, err := gitrepo.FindUniqueCommitHashAbbreviation(ctx, cx, guide.Commit)
+		if err != nil {
+			return nil, fmt.Errorf("abbreviate commit hash: %w", err)
+		}
+
+		info.Commit = guide.Commit.Hash.String()
+		info.ShortCommit = abbreviatedHash
+		info.CommitDate = guide.Commit.Committer.When
+
+		// Add the commit hash to the version if requested.
+		if opts.AddCommitHash {
+			v, err := spec.Version.SetMetadata("g" + abbreviatedHash)
+			if err != nil {
+				return nil, fmt.Errorf("set metadata: %w", err)
+			}
+			spec = spec.WithVersion(v)
+		}
+	}
+
+	info.setSpec(spec)
+	return info, nil
+}
+
+// setSpec fills the version fields of info from spec.
+func (info *Info) setSpec(spec gitrepo.VersionSpec) {
+	info.Spec = spec
+	info.Version = spec.String()
+	info.SemVer = spec.WithScope(gitrepo.RootScope()).WithPrefix("").String()
+	info.Scope = spec.Scope.String()
+	info.Prefix = spec.Prefix
+	info.Major = spec.Version.Major()
+	info.Minor = spec.Version.Minor()
+	info.Patch = spec.Version.Patch()
+	info.Prerelease = spec.Version.Prerelease()
+	info.PrereleaseIdentifiers = nil
+	if info.Prerelease != "" {
+		info.PrereleaseIdentifiers = strings.Split(info.Prerelease, ".")
+	}
+	info.Metadata = spec.Version.Metadata()
+}
@@ -0,0 +1,191 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package versioninfo_test
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0x5a17ed/semverkzeug/internal/gitfixture"
+	"github.com/0x5a17ed/semverkzeug/internal/gitrepo"
+	"github.com/0x5a17ed/semverkzeug/internal/versioninfo"
+)
+
+func TestCollect(t *testing.T) {
+	t.Run("tagged-clean", func(t *testing.T) {
+		cx := gitfixture.RepoWithOneCommitOneTagClean(t)
+		head := gitfixture.Head(t, cx)
+
+		info, err := versioninfo.Collect(t.Context(), cx, head, gitrepo.RootScope(), versioninfo.Options{})
+		require.NoError(t, err)
+
+		assert.Equal(t, "v0.1.0", info.Version)
+		assert.Equal(t, "0.1.0", info.SemVer)
+		assert.Equal(t, "v", info.Prefix)
+		assert.Equal(t, uint64(1), info.Minor)
+		assert.Empty(t, info.Prerelease)
+		assert.Nil(t, info.PrereleaseIdentifiers)
+		assert.Equal(t, "v0.1.0", info.BaseTag)
+		assert.Equal(t, 0, info.Depth)
+		assert.False(t, info.Dirty)
+		assert.Equal(t, head.Hash().String(), info.Commit)
+		assert.Equal(t, info.Commit[:len(info.ShortCommit)], info.ShortCommit)
+		assert.True(t, info.CommitDate.Equal(gitfixture.TestSig.When))
+	})
+
+	t.Run("calver", func(t *testing.T) {
+		cx := gitfixture.RepoWithOneCommitNoTagsClean(t)
+		gitfixture.CreateTag(t, cx, "v2026.01.2")
+		scheme, err := gitrepo.ParseCalVer("YYYY.0M.MICRO")
+		require.NoError(t, err)
+		cx.SetScheme(scheme)
+
+		info, err := versioninfo.Collect(t.Context(), cx, gitfixture.Head(t, cx), gitrepo.RootScope(), versioninfo.Options{})
+		require.NoError(t, err)
+
+		assert.Equal(t, "v2026.01.2", info.Version)
+		assert.Equal(t, "2026.01.2", info.SemVer)
+		assert.Equal(t, uint64(1), info.Minor)
+	})
+
+	t.Run("past-tag-dirty", func(t *testing.T) {
+		cx := gitfixture.RepoWithTwoCommitsOneTagDirty(t)
+
+		info, err := versioninfo.Collect(t.Context(), cx, gitfixture.Head(t, cx), gitrepo.RootScope(), versioninfo.Options{
+			AddCommitHash: true,
+		})
+		require.NoError(t, err)
+
+		assert.Regexp(t, `^v0\.1\.1-dev\.\d{6}T\d{8}Z\+g[0-9a-f]{7,}$`, info.Version)
+		assert.Equal(t, uint64(1), info.Patch)
+		require.Len(t, info.PrereleaseIdentifiers, 2)
+		assert.Equal(t, "dev", info.PrereleaseIdentifiers[0])
+		assert.Equal(t, "g"+info.ShortCommit, info.Metadata)
+		assert.Equal(t, 1, info.Depth)
+		assert.True(t, info.Dirty)
+	})
+
+	t.Run("explain", func(t *testing.T) {
+		cx := gitfixture.RepoWithTwoCommitsOneTagDirty(t)
+
+		info, err := versioninfo.Collect(t.Context(), cx, gitfixture.Head(t, cx), gitrepo.RootScope(), versioninfo.Options{
+			Explain: true,
+		})
+		require.NoError(t, err)
+
+		ex := info.Explanation
+		require.NotNil(t, ex)
+		require.Len(t, ex.Candidates, 1)
+		assert.True(t, ex.Candidates[0].Chosen)
+		assert.Equal(t, 1, ex.Guide.Depth)
+		assert.True(t, ex.Resolution.PatchBumped)
+		assert.Equal(t, info.Spec, ex.Resolution.Spec)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		cx := gitfixture.RepoEmpty(t)
+
+		info, err := versioninfo.Collect(t.Context(), cx, nil, gitrepo.RootScope(), versioninfo.Options{AddCommitHash: true})
+		require.NoError(t, err)
+
+		assert.Equal(t, "v0.0.1-dev.0", info.Version)
+		assert.Empty(t, info.Commit)
+		assert.Empty(t, info.BaseTag)
+		assert.Nil(t, info.Explanation)
+	})
+}
+
+func TestRender(t *testing.T) {
+	info := &versioninfo.Info{
+		Version:               "v1.2.3-rc.1",
+		Major:                 1,
+		PrereleaseIdentifiers: []string{"rc", "1"},
+	}
+
+	got, err := versioninfo.Render("t", `{{.Version}} {{.Major}} {{join .PrereleaseIdentifiers "/"}}`, info)
+	require.NoError(t, err)
+	assert.Equal(t, "v1.2.3-rc.1 1 rc/1", string(got))
+
+	_, err = versioninfo.Render("t", `{{.Unknown}}`, info)
+	assert.Error(t, err)
+}
+
+func TestWriteFileIfChanged(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "out.go")
+
+	changed, err := versioninfo.WriteFileIfChanged(p, []byte("a"))
+	require.NoError(t, err)
+	assert.True(t, changed)
+
+	past := time.Now().Add(-time.Hour).Truncate(time.Second)
+	require.NoError(t, os.Chtimes(p, past, past))
+
+	changed, err = versioninfo.WriteFileIfChanged(p, []byte("a"))
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	fi, err := os.Stat(p)
+	require.NoError(t, err)
+	assert.True(t, fi.ModTime().Equal(past), "unchanged file must keep its mtime")
+
+	require.NoError(t, os.Chmod(p, 0o600))
+	changed, err = versioninfo.WriteFileIfChanged(p, []byte("b"))
+	require.NoError(t, err)
+	assert.True(t, changed)
+
+	fi, err = os.Stat(p)
+	require.NoError(t, err)
+	assert.Equal(t, fs.FileMode(0o600), fi.Mode().Perm(), "changed file must keep its permissions")
+}
+
+func TestInfo_Environ(t *testing.T) {
+	info := &versioninfo.Info{
+		Version:    "mod/v1.2.3-rc.1",
+		SemVer:     "1.2.3-rc.1",
+		Scope:      "mod",
+		Prefix:     "v",
+		Major:      1,
+		Minor:      2,
+		Patch:      3,
+		Prerelease: "rc.1",
+		Commit:     "0123456789abcdef0123456789abcdef01234567",
+		CommitDate: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Depth:      4,
+		Dirty:      true,
+	}
+
+	got := info.Environ()
+
+	assert.Contains(t, got, "SEMVER=1.2.3-rc.1")
+	assert.Contains(t, got, "SEMVER_VERSION=mod/v1.2.3-rc.1")
+	assert.Contains(t, got, "SEMVER_SCOPE=mod")
+	assert.Contains(t, got, "SEMVER_MAJOR=1")
+	assert.Contains(t, got, "SEMVER_MINOR=2")
+	assert.Contains(t, got, "SEMVER_PATCH=3")
+	assert.Contains(t, got, "SEMVER_PRERELEASE=rc.1")
+	assert.Contains(t, got, "SEMVER_METADATA=")
+	assert.Contains(t, got, "SEMVER_COMMIT=0123456789abcdef0123456789abcdef01234567")
+	assert.Contains(t, got, "SEMVER_COMMIT_DATE=2026-01-02T03:04:05Z")
+	assert.Contains(t, got, "SEMVER_DEPTH=4")
+	assert.Contains(t, got, "SEMVER_DIRTY=true")
+}
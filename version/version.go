@@ -0,0 +1,331 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+// Package version derives semantic versions from the tags of a git
+// repository like the semverkzeug command does without flags, for
+// programs that want to do so in-process instead of running the
+// command.  Options covers the flags that change which tags count and
+// how the version is labeled; calendar versions (--calver), branch
+// labels (--branch-labels) and maintenance branches
+// (--maintenance-branch) are not supported.
+//
+// Unlike the packages below internal/, the API of this package is
+// stable.
+package version
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/go-git/go-git/v5/plumbing"
+
+	"github.com/0x5a17ed/semverkzeug/internal/bumper"
+	"github.com/0x5a17ed/semverkzeug/internal/floatingversion"
+	"github.com/0x5a17ed/semverkzeug/internal/gitrepo"
+)
+
+// DevScheme selects how the dev label of an unreleased version, like
+// the "dev.240101T00000000Z" of "v0.1.1-dev.240101T00000000Z", is
+// derived.
+type DevScheme int
+
+const (
+	// DevWorktree dates a dirty worktree by its most recently
+	// modified file, and a clean one by the commit.  It is what the
+	// describe command does.
+	DevWorktree DevScheme = iota
+
+	// DevCommitted ignores the worktree and always dates the version
+	// by the commit, making it reproducible.
+	DevCommitted
+)
+
+// Part is the part of a version to bump.
+type Part int
+
+const (
+	Major Part = iota + 1
+	Minor
+	Patch
+)
+
+// bumperPart maps p to the internal representation.
+func (p Part) bumperPart() (bumper.Part, error) {
+	switch p {
+	case Major:
+		return bumper.Major, nil
+	case Minor:
+		return bumper.Minor, nil
+	case Patch:
+		return bumper.Patch, nil
+	}
+	return nil, fmt.Errorf("invalid version part %d", int(p))
+}
+
+// Options control how a version is derived.  The zero value describes
+// HEAD with unscoped tags, like the describe command does.
+type Options struct {
+	// Scope selects the tags of one scope, like "mod" for tags like
+	// "mod/v1.2.3".  Empty selects unscoped tags.
+	Scope string
+
+	// Ref is the revision to describe, like a branch, tag or commit
+	// hash.  HEAD when empty.
+	Ref string
+
+	// DevScheme selects how unreleased versions are labeled.
+	DevScheme DevScheme
+
+	// Prefix, when not empty, replaces the prefix of the version,
+	// like the "v" of "v1.2.3".
+	Prefix string
+
+	// AddCommitHash adds the abbreviated commit hash as build
+	// metadata.
+	AddCommitHash bool
+
+	// CoerceTags also derives versions from tags that are almost
+	// semantic versions, like "v1.2" (v1.2.0) or "1.2.3.4" (1.2.3+4),
+	// which are ignored otherwise.
+	CoerceTags bool
+}
+
+// Spec is a version as it appears in a tag name.
+type Spec struct {
+	// Scope is the scope of the tag, like "mod" for "mod/v1.2.3", or
+	// empty.
+	Scope string
+
+	// Prefix is the prefix of the version, like "v", or empty.
+	Prefix string
+
+	// Version is the semantic version.
+	Version *semver.Version
+}
+
+// String returns the version as a tag name, with scope and prefix.
+func (s Spec) String() string {
+	if s.Scope == "" {
+		return s.Prefix + s.Version.String()
+	}
+	return s.Scope + "/" + s.Prefix + s.Version.String()
+}
+
+func newSpec(s gitrepo.VersionSpec) Spec {
+	return Spec{
+		Scope:   s.Scope.String(),
+		Prefix:  s.Prefix,
+		Version: new(s.Version),
+	}
+}
+
+// Tag is a version tag.
+type Tag struct {
+	// Name is the name of the tag, like "v1.2.3".
+	Name string
+
+	// Spec is the version the tag names.
+	Spec Spec
+
+	// Commit is the hash of the tagged commit.
+	Commit string
+
+	// Annotated reports whether the tag is an annotated tag.
+	Annotated bool
+
+	// Date is the tagger date of an annotated tag, or the commit
+	// date of a lightweight one.
+	Date time.Time
+
+	// Coerced reports whether Name is not a valid version tag and Spec
+	// was coerced from it.  See Options.CoerceTags.
+	Coerced bool
+}
+
+func newTag(vt gitrepo.VersionTag) Tag {
+	return Tag{
+		Name:      vt.TagName,
+		Spec:      newSpec(vt.VersionSpec),
+		Commit:    vt.CommitHash.String(),
+		Annotated: vt.IsAnnotated,
+		Date:      vt.TagDate,
+		Coerced:   vt.Coerced,
+	}
+}
+
+// Description is the version of a commit and what it was derived
+// from.
+type Description struct {
+	// Spec is the version.
+	Spec Spec
+
+	// Commit is the hash of the described commit and ShortCommit its
+	// shortest unique abbreviation.  Both are empty for an empty
+	// repository.
+	Commit      string
+	ShortCommit string
+
+	// CommitDate is the committer date of the described commit.
+	CommitDate time.Time
+
+	// Tags are the tags of the highest version whose history Commit
+	// shares, which the version derives from.  Empty when no version
+	// tag is reachable.
+	Tags []Tag
+
+	// MergeBase is the hash of the commit where the histories of
+	// Commit and Tags meet, empty without Tags.
+	MergeBase string
+
+	// Depth is the number of commits since MergeBase, or, without
+	// Tags, the number of commits reachable from Commit.
+	Depth int
+
+	// Dirty reports whether the worktree has uncommitted changes.
+	// Always false with DevCommitted.
+	Dirty bool
+
+	// Unreliable reports that the repository is a shallow clone in
+	// which no version tag is reachable, so the version most likely
+	// restarted at the initial one.
+	Unreliable bool
+}
+
+// String returns the version as a tag name.
+func (d *Description) String() string {
+	return d.Spec.String()
+}
+
+// Describe derives the version of the commit opts.Ref names in the
+// repository containing repoPath.  Walking the history stops with the
+// error of ctx once ctx is done.
+func Describe(ctx context.Context, repoPath string, opts Options) (*Description, error) {
+	cx, ref, scope, err := open(ctx, repoPath, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	guide, err := gitrepo.BuildGuide(ctx, cx, ref, scope)
+	if err != nil {
+		return nil, fmt.Errorf("build guide: %w", err)
+	}
+
+	var res floatingversion.Result
+	if opts.DevScheme == DevCommitted {
+		res, err = floatingversion.ResolveCommitted(guide)
+	} else {
+		res, err = floatingversion.Resolve(ctx, cx, guide)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Description{
+		Depth:      guide.Depth,
+		Dirty:      res.Dirty,
+		Unreliable: guide.IsUnreliable(),
+	}
+	for _, vt := range guide.Tags {
+		d.Tags = append(d.Tags, newTag(vt))
+	}
+	if guide.MergeBase != nil {
+		d.MergeBase = guide.MergeBase.Hash.String()
+	}
+
+	spec := res.Spec
+	if guide.HasCommit() {
+		d.Commit = guide.Commit.Hash.String()
+		d.CommitDate = guide.Commit.Committer.When
+		if d.ShortCommit, err = gitrepo.FindUniqueCommitHashAbbreviation(ctx, cx, guide.Commit); err != nil {
+			return nil, fmt.Errorf("abbreviate commit hash: %w", err)
+		}
+
+		if opts.AddCommitHash {
+			v, err := spec.Version.SetMetadata("g" + d.ShortCommit)
+			if err != nil {
+				return nil, fmt.Errorf("set metadata: %w", err)
+			}
+			spec = spec.WithVersion(v)
+		}
+	}
+
+	d.Spec = newSpec(withPrefix(spec, opts))
+	return d, nil
+}
+
+// Next returns the version bumping part of the last released version
+// reachable from opts.Ref would result in, without creating a tag.
+func Next(ctx context.Context, repoPath string, part Part, opts Options) (Spec, error) {
+	bp, err := part.bumperPart()
+	if err != nil {
+		return Spec{}, err
+	}
+
+	cx, ref, scope, err := open(ctx, repoPath, opts)
+	if err != nil {
+		return Spec{}, err
+	}
+
+	guide, err := gitrepo.BuildGuide(ctx, cx, ref, scope)
+	if err != nil {
+		return Spec{}, fmt.Errorf("build guide: %w", err)
+	}
+
+	spec := gitrepo.LatestSpec(guide)
+	spec = spec.WithVersion(bumper.Bump(spec.Version, bp))
+	return newSpec(withPrefix(spec, opts)), nil
+}
+
+// open opens the repository containing repoPath and resolves the ref
+// and scope of opts.  The ref is nil for an empty repository.
+func open(ctx context.Context, repoPath string, opts Options) (*gitrepo.Context, *plumbing.Reference, gitrepo.Scope, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, gitrepo.Scope{}, err
+	}
+
+	scope, err := gitrepo.ParseScope(opts.Scope)
+	if err != nil {
+		return nil, nil, gitrepo.Scope{}, err
+	}
+
+	cx, err := gitrepo.NewContextFromPath(repoPath)
+	if err != nil {
+		return nil, nil, gitrepo.Scope{}, err
+	}
+	cx.SetCoerceTags(opts.CoerceTags)
+
+	if opts.Ref != "" {
+		ref, err := gitrepo.ResolveRef(cx, opts.Ref)
+		return cx, ref, scope, err
+	}
+
+	head, err := cx.Repository().Head()
+	if err != nil && !errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return nil, nil, gitrepo.Scope{}, fmt.Errorf("resolve HEAD: %w", err)
+	}
+	return cx, head, scope, nil
+}
+
+// withPrefix applies opts.Prefix to spec.
+func withPrefix(spec gitrepo.VersionSpec, opts Options) gitrepo.VersionSpec {
+	if opts.Prefix == "" {
+		return spec
+	}
+	return spec.WithPrefix(opts.Prefix)
+}
@@ -0,0 +1,167 @@
+/*
+ * Copyright(C) 2026 the semverkzeug developers
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * <https://www.apache.org/licenses/LICENSE-2.0>
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied.  See the License for the specific
+ * language governing permissions and limitations under the License.
+ */
+
+package version_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0x5a17ed/semverkzeug/internal/gitfixture"
+	"github.com/0x5a17ed/semverkzeug/version"
+)
+
+// newRepo creates a repository on disk:
+//
+//	A [v0.1.0, mod/v2.0.0] -- B
+//
+// and returns its path.
+func newRepo(t *testing.T) string {
+	t.Helper()
+
+	_ = gitfixture.NewHomeFixture(t)
+	gitfixture.IsolateGitConfig(t)
+
+	dir := t.TempDir()
+	gitfixture.RunGit(t, dir, "init", "-q", "-b", "main")
+	for _, name := range []string{"foo", "bar"} {
+		gitfixture.WriteFile(t, filepath.Join(dir, name), "baa")
+		gitfixture.RunGit(t, dir, "add", ".")
+		gitfixture.RunGit(t, dir, "commit", "-q", "-m", name)
+		if name == "foo" {
+			gitfixture.RunGit(t, dir, "tag", "-a", "-m", "v0.1.0", "v0.1.0")
+			gitfixture.RunGit(t, dir, "tag", "-a", "-m", "mod/v2.0.0", "mod/v2.0.0")
+		}
+	}
+	return dir
+}
+
+func TestDescribe(t *testing.T) {
+	dir := newRepo(t)
+
+	tests := []struct {
+		name  string
+		opts  version.Options
+		dirty bool
+		want  string
+		depth int
+	}{
+		{name: "head", want: "v0.1.1-dev.240101T00000000Z", depth: 1},
+		{name: "tag", opts: version.Options{Ref: "v0.1.0"}, want: "v0.1.0"},
+		{name: "scope", opts: version.Options{Ref: "main~1", Scope: "mod"}, want: "mod/v2.0.0"},
+		{name: "prefix", opts: version.Options{Ref: "v0.1.0", Prefix: "release-"}, want: "release-0.1.0"},
+		{
+			name: "commit-hash",
+			opts: version.Options{Ref: "v0.1.0", AddCommitHash: true},
+			want: "v0.1.0+g" + gitfixture.RunGit(t, dir, "rev-parse", "--short", "v0.1.0^{commit}"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			got, err := version.Describe(t.Context(), dir, tt.opts)
+			require.NoError(t, err)
+
+			// Assert
+			assert.Equal(t, tt.want, got.String())
+			assert.Equal(t, tt.depth, got.Depth)
+			assert.False(t, got.Dirty)
+			assert.False(t, got.Unreliable)
+			require.NotEmpty(t, got.Tags)
+			assert.True(t, got.Tags[0].Annotated)
+		})
+	}
+}
+
+func TestDescribe_DevScheme(t *testing.T) {
+	// Arrange
+	dir := newRepo(t)
+	gitfixture.WriteFile(t, filepath.Join(dir, "foo"), "dirty")
+
+	// Act
+	worktree, err := version.Describe(t.Context(), dir, version.Options{})
+	require.NoError(t, err)
+	committed, err := version.Describe(t.Context(), dir, version.Options{DevScheme: version.DevCommitted})
+	require.NoError(t, err)
+
+	// Assert: Only the worktree scheme looks at the dirty file.
+	assert.True(t, worktree.Dirty)
+	assert.NotEqual(t, "v0.1.1-dev.240101T00000000Z", worktree.String())
+	assert.False(t, committed.Dirty)
+	assert.Equal(t, "v0.1.1-dev.240101T00000000Z", committed.String())
+}
+
+func TestDescribe_EmptyRepository(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	gitfixture.RunGit(t, dir, "init", "-q")
+
+	// Act
+	got, err := version.Describe(t.Context(), dir, version.Options{})
+	require.NoError(t, err)
+
+	// Assert
+	assert.Empty(t, got.Commit)
+	assert.Empty(t, got.Tags)
+}
+
+func TestDescribe_Canceled(t *testing.T) {
+	// Arrange
+	dir := newRepo(t)
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	// Act
+	_, err := version.Describe(ctx, dir, version.Options{})
+
+	// Assert
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestNext(t *testing.T) {
+	dir := newRepo(t)
+
+	tests := []struct {
+		part version.Part
+		opts version.Options
+		want string
+	}{
+		{part: version.Patch, want: "v0.1.1"},
+		{part: version.Minor, want: "v0.2.0"},
+		{part: version.Major, want: "v1.0.0"},
+		{part: version.Minor, opts: version.Options{Scope: "mod"}, want: "mod/v2.1.0"},
+		{part: version.Patch, opts: version.Options{Prefix: "release-"}, want: "release-0.1.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			// Act
+			got, err := version.Next(t.Context(), dir, tt.part, tt.opts)
+			require.NoError(t, err)
+
+			// Assert
+			assert.Equal(t, tt.want, got.String())
+		})
+	}
+
+	t.Run("invalid-part", func(t *testing.T) {
+		_, err := version.Next(t.Context(), dir, 0, version.Options{})
+		assert.Error(t, err)
+	})
+}